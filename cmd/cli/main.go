@@ -13,9 +13,14 @@ import (
 )
 
 var (
-	inputPath  string
-	outputPath string
-	verbose    bool
+	inputPath     string
+	outputPath    string
+	verbose       bool
+	classHelpers  []string
+	themePath     string
+	colorFormat   string
+	colorFallback bool
+	darkMode      string
 )
 
 var rootCmd = &cobra.Command{
@@ -29,6 +34,11 @@ func init() {
 	rootCmd.Flags().StringVarP(&inputPath, "input", "i", "", "Input file or directory")
 	rootCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output directory")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.Flags().StringSliceVar(&classHelpers, "class-helpers", nil, "Class helper functions whose arguments hold classes (default clsx,cn,classnames,classNames,cx,twMerge,twJoin)")
+	rootCmd.Flags().StringVar(&themePath, "theme", "", "Stylesheet with Tailwind v4 @theme blocks to take design tokens from")
+	rootCmd.Flags().StringVar(&colorFormat, "color-format", "oklch", "Colour output format: oklch, hex, rgb or var")
+	rootCmd.Flags().BoolVar(&colorFallback, "color-fallback", false, "Precede color-mix() opacity values with an rgba() fallback for older browsers")
+	rootCmd.Flags().StringVar(&darkMode, "dark-mode", "media", "Dark mode strategy: media, class (a .dark ancestor) or a selector such as '[data-theme=dark] &'")
 	rootCmd.MarkFlagRequired("input")
 	rootCmd.MarkFlagRequired("output")
 }
@@ -45,8 +55,31 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Load design tokens
+	options := converter.DefaultOptions()
+	if themePath != "" {
+		if err := options.Theme.ParseFile(themePath); err != nil {
+			fmt.Printf("Error reading theme: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	format, err := converter.ParseColorFormat(colorFormat)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	options.ColorFormat = format
+	options.ColorFallback = colorFallback
+
+	options.DarkMode, err = converter.ParseDarkMode(darkMode)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Process files
-	if err := processPath(inputPath, outputPath); err != nil {
+	if err := processPath(inputPath, outputPath, options); err != nil {
 		fmt.Printf("Error processing files: %v\n", err)
 		os.Exit(1)
 	}
@@ -54,7 +87,11 @@ func run(cmd *cobra.Command, args []string) {
 	fmt.Println("Conversion completed successfully!")
 }
 
-func processPath(input, output string) error {
+func processPath(input, output string, options converter.Options) error {
+	// Classes only count as Tailwind when they convert, so custom classes
+	// sharing a root, like col-md-6, stay in the markup
+	mapper := converter.NewConverterWithOptions(options)
+
 	return filepath.Walk(input, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -76,22 +113,29 @@ func processPath(input, output string) error {
 
 		// Parse file
 		htmlParser := parser.NewHTMLParser()
+		htmlParser.SetUtilityMapper(mapper.Maps)
+		if len(classHelpers) > 0 {
+			htmlParser.SetClassHelpers(classHelpers)
+		}
 		document, err := htmlParser.ParseFile(path)
 		if err != nil {
 			return fmt.Errorf("error parsing %s: %v", path, err)
 		}
 
-		// Extract classes
+		// Extract classes, one group per element instance
 		classExtractor := parser.NewClassExtractor()
-		classes := classExtractor.Extract(document)
+		groups := classExtractor.Extract(document)
 
-		if len(classes) == 0 {
+		if len(groups) == 0 {
 			return nil // No Tailwind classes found
 		}
 
 		// Convert classes
-		conv := converter.NewConverter()
-		cssRules, semanticMapping := conv.Convert(classes)
+		conv := converter.NewConverterWithOptions(options)
+		cssRules, semanticMapping := conv.Convert(groups)
+		for _, diagnostic := range conv.Diagnostics() {
+			fmt.Printf("Warning: %s:%d:%d: %s: %s\n", path, diagnostic.Line, diagnostic.Column, diagnostic.Class, diagnostic.Message)
+		}
 
 		// Generate output files
 		relPath, _ := filepath.Rel(input, path)
@@ -118,6 +162,7 @@ func processPath(input, output string) error {
 
 		// Generate updated HTML file
 		htmlGen := generator.NewHTMLGenerator()
+		htmlGen.SetParser(htmlParser)
 		htmlPath := filepath.Join(outputDir, baseName)
 		if err := htmlGen.Generate(document, semanticMapping, htmlPath, cleanBaseName); err != nil {
 			return err
@@ -0,0 +1,150 @@
+package converter
+
+import "tailwind-v4-to-css-converter/internal/parser"
+
+const colorTransitionProperties = "color, background-color, border-color, outline-color, text-decoration-color, fill, stroke, --tw-gradient-from, --tw-gradient-via, --tw-gradient-to"
+
+// Properties animated by transition and transition-*
+var transitionProperties = map[string]string{
+	"":          colorTransitionProperties + ", opacity, box-shadow, transform, translate, scale, rotate, filter, -webkit-backdrop-filter, backdrop-filter, display, content-visibility, overlay, pointer-events",
+	"all":       "all",
+	"colors":    colorTransitionProperties,
+	"opacity":   "opacity",
+	"shadow":    "box-shadow",
+	"transform": "transform, translate, scale, rotate",
+}
+
+func (tm *TailwindMappings) initAnimationMappings() {
+	// Transitions, whose timing comes from duration-* and ease-* through
+	// --tw-duration and --tw-ease, or the theme's defaults
+	tm.staticMappings["transition-none"] = []CSSProperty{{Name: "transition-property", Value: "none"}}
+	tm.staticMappings["transition-discrete"] = []CSSProperty{{Name: "transition-behavior", Value: "allow-discrete"}}
+	tm.staticMappings["transition-normal"] = []CSSProperty{{Name: "transition-behavior", Value: "normal"}}
+	tm.addDynamic("transition", func(u *parser.ParsedUtility) []CSSProperty {
+		property := arbitraryValue(u)
+		if u.Value == nil && u.Modifier == nil && !u.Negative {
+			property = transitionProperties[""]
+		} else if value := namedValue(u); value != "" {
+			property = transitionProperties[value]
+		}
+		if property == "" {
+			return nil
+		}
+
+		ease, _ := tm.theme.Value("--default-transition-timing-function")
+		if ease == "" {
+			ease = "ease"
+		}
+		duration, _ := tm.theme.Value("--default-transition-duration")
+		if duration == "" {
+			duration = "0s"
+		}
+		return []CSSProperty{
+			{Name: "transition-property", Value: property},
+			{Name: "transition-timing-function", Value: "var(--tw-ease, " + ease + ")"},
+			{Name: "transition-duration", Value: "var(--tw-duration, " + duration + ")"},
+		}
+	})
+
+	// Timing, duration-300, ease-out and delay-150
+	tm.addDynamic("duration", func(u *parser.ParsedUtility) []CSSProperty {
+		if duration := timeValue(u); duration != "" {
+			return []CSSProperty{
+				{Name: "--tw-duration", Value: duration},
+				{Name: "transition-duration", Value: duration},
+			}
+		}
+		return nil
+	})
+	tm.addDynamic("ease", func(u *parser.ParsedUtility) []CSSProperty {
+		ease := arbitraryValue(u)
+		switch value := namedValue(u); value {
+		case "":
+		case "linear", "initial":
+			ease = value
+		default:
+			ease, _ = tm.themeValue("ease", u)
+		}
+		if ease == "" {
+			return nil
+		}
+		return []CSSProperty{
+			{Name: "--tw-ease", Value: ease},
+			{Name: "transition-timing-function", Value: ease},
+		}
+	})
+	tm.addDynamic("delay", func(u *parser.ParsedUtility) []CSSProperty {
+		if delay := timeValue(u); delay != "" && delay != "initial" {
+			return []CSSProperty{{Name: "transition-delay", Value: delay}}
+		}
+		return nil
+	})
+
+	// Animations from the theme's --animate-* tokens; the @keyframes they
+	// name are added to the module by keyframeRules
+	tm.addDynamic("animate", func(u *parser.ParsedUtility) []CSSProperty {
+		animation := arbitraryValue(u)
+		if value := namedValue(u); value == "none" {
+			animation = value
+		} else if value != "" {
+			animation, _ = tm.themeValue("animate", u)
+		}
+		if animation == "" {
+			return nil
+		}
+		return []CSSProperty{{Name: "animation", Value: animation}}
+	})
+}
+
+// timeValue returns the milliseconds of a timing utility like duration-300,
+// initial, or an arbitrary value
+func timeValue(u *parser.ParsedUtility) string {
+	switch value := namedValue(u); {
+	case numberPattern.MatchString(value):
+		return value + "ms"
+	case value == "initial":
+		return value
+	}
+	return arbitraryValue(u)
+}
+
+// keyframeRules returns the @keyframes of the theme animations the rules
+// use, once each in order of first use
+func (tm *TailwindMappings) keyframeRules(rules []CSSRule) []CSSRule {
+	seen := make(map[string]bool)
+	var keyframeRules []CSSRule
+
+	for _, rule := range rules {
+		for _, prop := range rule.Properties {
+			if prop.Name != "animation" && prop.Name != "animation-name" {
+				continue
+			}
+			for _, animation := range splitSelectorList(prop.Value) {
+				for _, name := range topLevelFields(animation) {
+					keyframes, exists := tm.theme.Keyframes(name)
+					if !exists || seen[name] {
+						continue
+					}
+					seen[name] = true
+					keyframeRules = append(keyframeRules, stepRules(keyframes)...)
+				}
+			}
+		}
+	}
+
+	return keyframeRules
+}
+
+// stepRules writes the steps of an @keyframes as rules inside it
+func stepRules(keyframes parser.Keyframes) []CSSRule {
+	atRule := "@keyframes " + keyframes.Name
+	rules := make([]CSSRule, 0, len(keyframes.Steps))
+	for _, step := range keyframes.Steps {
+		rule := CSSRule{AtRules: []string{atRule}, Selector: step.Selector}
+		for _, declaration := range step.Declarations {
+			rule.Properties = append(rule.Properties, CSSProperty{Name: declaration.Property, Value: declaration.Value})
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+const wantTransitionProperty = "transition-property: color, background-color, border-color, outline-color, text-decoration-color, fill, stroke, --tw-gradient-from, --tw-gradient-via, --tw-gradient-to, opacity, box-shadow, transform, translate, scale, rotate, filter, -webkit-backdrop-filter, backdrop-filter, display, content-visibility, overlay, pointer-events"
+
+func TestKeyframesEmittedOnce(t *testing.T) {
+	rules, _ := convertMarkup(t, `<div class="animate-spin"><span class="animate-ping hover:animate-spin"></span><p class="animate-spin"></p></div>`)
+	got, _ := render(rules)
+	want := []string{
+		"{ .div_1 } animation: spin 1s linear infinite",
+		"{ .span_2 } animation: ping 1s cubic-bezier(0, 0, 0.2, 1) infinite",
+		"@media (hover: hover) { .span_2:hover } animation: spin 1s linear infinite",
+		"{ .p_3 } animation: spin 1s linear infinite",
+		"@keyframes spin { to } transform: rotate(360deg)",
+		"@keyframes ping { 75%, 100% } transform: scale(2); opacity: 0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnimations(t *testing.T) {
+	tests := []struct {
+		classes string
+		want    []string
+	}{
+		{
+			classes: "animate-bounce",
+			want: []string{
+				"{ .div_1 } animation: bounce 1s infinite",
+				"@keyframes bounce { 0%, 100% } transform: translateY(-25%); animation-timing-function: cubic-bezier(0.8, 0, 1, 1)",
+				"@keyframes bounce { 50% } transform: none; animation-timing-function: cubic-bezier(0, 0, 0.2, 1)",
+			},
+		},
+		{
+			classes: "animate-none",
+			want:    []string{"{ .div_1 } animation: none"},
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, tt.want, nil)
+	}
+}
+
+func TestTransitionComposition(t *testing.T) {
+	tests := []struct {
+		classes    string
+		want       string
+		properties []string
+	}{
+		{
+			classes: "transition",
+			want: "{ .div_1 } " + wantTransitionProperty +
+				"; transition-timing-function: var(--tw-ease, cubic-bezier(0.4, 0, 0.2, 1)); transition-duration: var(--tw-duration, 150ms)",
+			properties: []string{"--tw-gradient-from", "--tw-gradient-via", "--tw-gradient-to", "--tw-ease", "--tw-duration"},
+		},
+		{
+			classes: "transition ease-in-out duration-300 delay-150",
+			want: "{ .div_1 } " + wantTransitionProperty +
+				"; transition-delay: 150ms; --tw-duration: 300ms; transition-duration: 300ms; --tw-ease: cubic-bezier(0.4, 0, 0.2, 1); transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1)",
+			properties: []string{"--tw-gradient-from", "--tw-gradient-via", "--tw-gradient-to", "--tw-duration", "--tw-ease"},
+		},
+		{
+			classes: "ease-in-out transition",
+			want: "{ .div_1 } " + wantTransitionProperty +
+				"; transition-duration: var(--tw-duration, 150ms); --tw-ease: cubic-bezier(0.4, 0, 0.2, 1); transition-timing-function: cubic-bezier(0.4, 0, 0.2, 1)",
+		},
+		{
+			classes:    "transition-opacity duration-500 ease-out",
+			want:       "{ .div_1 } transition-property: opacity; --tw-duration: 500ms; transition-duration: 500ms; --tw-ease: cubic-bezier(0, 0, 0.2, 1); transition-timing-function: cubic-bezier(0, 0, 0.2, 1)",
+			properties: []string{"--tw-duration", "--tw-ease"},
+		},
+		{
+			classes: "duration-[250ms] ease-[cubic-bezier(0.4,0,0.2,1)]",
+			want:    "{ .div_1 } --tw-duration: 250ms; transition-duration: 250ms; --tw-ease: cubic-bezier(0.4,0,0.2,1); transition-timing-function: cubic-bezier(0.4,0,0.2,1)",
+		},
+		{
+			classes: "transition-none",
+			want:    "{ .div_1 } transition-property: none",
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, []string{tt.want}, tt.properties)
+	}
+}
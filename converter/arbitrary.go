@@ -0,0 +1,359 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// arbitraryMapping is one reading of a bracketed value, e.g. text-[#333] is
+// a colour and text-[14px] a font size. The first mapping whose data types
+// include the value's type wins; a mapping without data types takes any value.
+type arbitraryMapping struct {
+	dataTypes  []string
+	properties []string
+}
+
+var (
+	lengthPattern     = regexp.MustCompile(`^-?\d*\.?\d+(px|rem|em|ex|ch|lh|rlh|vw|vh|vi|vb|vmin|vmax|dvw|dvh|svw|svh|lvw|lvh|cqw|cqh|cqi|cqb|cqmin|cqmax|pt|pc|in|cm|mm|q)$`)
+	percentagePattern = regexp.MustCompile(`^-?\d*\.?\d+%$`)
+	anglePattern      = regexp.MustCompile(`^-?\d*\.?\d+(deg|rad|grad|turn)$`)
+	signedNumber      = regexp.MustCompile(`^-?\d*\.?\d+$`)
+)
+
+// Colour functions and keywords recognised in untyped arbitrary values
+var colorFunctions = []string{
+	"rgb(", "rgba(", "hsl(", "hsla(", "hwb(", "lab(", "lch(", "oklab(",
+	"oklch(", "color(", "color-mix(", "light-dark(",
+}
+
+var colorKeywords = setOf(strings.Fields(`
+	transparent currentcolor currentColor aliceblue antiquewhite aqua aquamarine
+	azure beige bisque black blanchedalmond blue blueviolet brown burlywood
+	cadetblue chartreuse chocolate coral cornflowerblue cornsilk crimson cyan
+	darkblue darkcyan darkgoldenrod darkgray darkgreen darkgrey darkkhaki
+	darkmagenta darkolivegreen darkorange darkorchid darkred darksalmon
+	darkseagreen darkslateblue darkslategray darkslategrey darkturquoise
+	darkviolet deeppink deepskyblue dimgray dimgrey dodgerblue firebrick
+	floralwhite forestgreen fuchsia gainsboro ghostwhite gold goldenrod gray
+	green greenyellow grey honeydew hotpink indianred indigo ivory khaki
+	lavender lavenderblush lawngreen lemonchiffon lightblue lightcoral
+	lightcyan lightgoldenrodyellow lightgray lightgreen lightgrey lightpink
+	lightsalmon lightseagreen lightskyblue lightslategray lightslategrey
+	lightsteelblue lightyellow lime limegreen linen magenta maroon
+	mediumaquamarine mediumblue mediumorchid mediumpurple mediumseagreen
+	mediumslateblue mediumspringgreen mediumturquoise mediumvioletred
+	midnightblue mintcream mistyrose moccasin navajowhite navy oldlace olive
+	olivedrab orange orangered orchid palegoldenrod palegreen paleturquoise
+	palevioletred papayawhip peachpuff peru pink plum powderblue purple
+	rebeccapurple red rosybrown royalblue saddlebrown salmon sandybrown
+	seagreen seashell sienna silver skyblue slateblue slategray slategrey snow
+	springgreen steelblue tan teal thistle tomato turquoise violet wheat white
+	whitesmoke yellow yellowgreen`)...)
+
+var positionKeywords = setOf("left", "right", "top", "bottom", "center")
+
+// Keywords of border widths and font sizes, e.g. border-[thin], text-[small]
+var (
+	lineWidthKeywords    = setOf("thin", "medium", "thick")
+	absoluteSizeKeywords = setOf("xx-small", "x-small", "small", "medium", "large", "x-large", "xx-large", "xxx-large")
+)
+
+// Arbitrary values accepted per utility root, e.g. w-[37px] or bg-[url(...)]
+var arbitraryMappings = map[string][]arbitraryMapping{
+	// Layout
+	"aspect":   {{properties: []string{"aspect-ratio"}}},
+	"columns":  {{properties: []string{"columns"}}},
+	"object":   {{properties: []string{"object-position"}}},
+	"inset":    {{properties: []string{"inset"}}},
+	"inset-x":  {{properties: []string{"inset-inline"}}},
+	"inset-y":  {{properties: []string{"inset-block"}}},
+	"start":    {{properties: []string{"inset-inline-start"}}},
+	"end":      {{properties: []string{"inset-inline-end"}}},
+	"top":      {{properties: []string{"top"}}},
+	"right":    {{properties: []string{"right"}}},
+	"bottom":   {{properties: []string{"bottom"}}},
+	"left":     {{properties: []string{"left"}}},
+	"z":        {{properties: []string{"z-index"}}},
+	"overflow": {{properties: []string{"overflow"}}},
+
+	// Flexbox and grid
+	"basis":      {{properties: []string{"flex-basis"}}},
+	"flex":       {{properties: []string{"flex"}}},
+	"grow":       {{properties: []string{"flex-grow"}}},
+	"shrink":     {{properties: []string{"flex-shrink"}}},
+	"order":      {{properties: []string{"order"}}},
+	"grid-cols":  {{properties: []string{"grid-template-columns"}}},
+	"grid-rows":  {{properties: []string{"grid-template-rows"}}},
+	"col":        {{properties: []string{"grid-column"}}},
+	"col-start":  {{properties: []string{"grid-column-start"}}},
+	"col-end":    {{properties: []string{"grid-column-end"}}},
+	"row":        {{properties: []string{"grid-row"}}},
+	"row-start":  {{properties: []string{"grid-row-start"}}},
+	"row-end":    {{properties: []string{"grid-row-end"}}},
+	"grid-flow":  {{properties: []string{"grid-auto-flow"}}},
+	"auto-cols":  {{properties: []string{"grid-auto-columns"}}},
+	"auto-rows":  {{properties: []string{"grid-auto-rows"}}},
+	"gap":        {{properties: []string{"gap"}}},
+	"gap-x":      {{properties: []string{"column-gap"}}},
+	"gap-y":      {{properties: []string{"row-gap"}}},
+	"justify":    {{properties: []string{"justify-content"}}},
+	"items":      {{properties: []string{"align-items"}}},
+	"self":       {{properties: []string{"align-self"}}},
+	"place-self": {{properties: []string{"place-self"}}},
+
+	// Spacing
+	"p":  {{properties: []string{"padding"}}},
+	"px": {{properties: []string{"padding-left", "padding-right"}}},
+	"py": {{properties: []string{"padding-top", "padding-bottom"}}},
+	"ps": {{properties: []string{"padding-inline-start"}}},
+	"pe": {{properties: []string{"padding-inline-end"}}},
+	"pt": {{properties: []string{"padding-top"}}},
+	"pr": {{properties: []string{"padding-right"}}},
+	"pb": {{properties: []string{"padding-bottom"}}},
+	"pl": {{properties: []string{"padding-left"}}},
+	"m":  {{properties: []string{"margin"}}},
+	"mx": {{properties: []string{"margin-left", "margin-right"}}},
+	"my": {{properties: []string{"margin-top", "margin-bottom"}}},
+	"ms": {{properties: []string{"margin-inline-start"}}},
+	"me": {{properties: []string{"margin-inline-end"}}},
+	"mt": {{properties: []string{"margin-top"}}},
+	"mr": {{properties: []string{"margin-right"}}},
+	"mb": {{properties: []string{"margin-bottom"}}},
+	"ml": {{properties: []string{"margin-left"}}},
+
+	// Sizing
+	"size":  {{properties: []string{"width", "height"}}},
+	"w":     {{properties: []string{"width"}}},
+	"min-w": {{properties: []string{"min-width"}}},
+	"max-w": {{properties: []string{"max-width"}}},
+	"h":     {{properties: []string{"height"}}},
+	"min-h": {{properties: []string{"min-height"}}},
+	"max-h": {{properties: []string{"max-height"}}},
+
+	// Typography
+	"text": {
+		{dataTypes: []string{"color"}, properties: []string{"color"}},
+		{properties: []string{"font-size"}},
+	},
+	"font": {
+		{dataTypes: []string{"number"}, properties: []string{"font-weight"}},
+		{properties: []string{"font-family"}},
+	},
+	"leading":  {{properties: []string{"line-height"}}},
+	"tracking": {{properties: []string{"letter-spacing"}}},
+	"indent":   {{properties: []string{"text-indent"}}},
+	"align":    {{properties: []string{"vertical-align"}}},
+	"decoration": {
+		{dataTypes: []string{"color"}, properties: []string{"text-decoration-color"}},
+		{properties: []string{"text-decoration-thickness"}},
+	},
+	"underline-offset": {{properties: []string{"text-underline-offset"}}},
+	"list":             {{properties: []string{"list-style-type"}}},
+	"list-image":       {{properties: []string{"list-style-image"}}},
+
+	// Backgrounds
+	"bg": {
+		{dataTypes: []string{"color"}, properties: []string{"background-color"}},
+		{dataTypes: []string{"url", "image"}, properties: []string{"background-image"}},
+		{dataTypes: []string{"bg-size", "length"}, properties: []string{"background-size"}},
+		{dataTypes: []string{"position", "percentage"}, properties: []string{"background-position"}},
+		{properties: []string{"background-color"}},
+	},
+
+	// Borders and outlines
+	"border":   borderMappings("border"),
+	"border-x": borderMappings("border-left", "border-right"),
+	"border-y": borderMappings("border-top", "border-bottom"),
+	"border-s": borderMappings("border-inline-start"),
+	"border-e": borderMappings("border-inline-end"),
+	"border-t": borderMappings("border-top"),
+	"border-r": borderMappings("border-right"),
+	"border-b": borderMappings("border-bottom"),
+	"border-l": borderMappings("border-left"),
+	"outline": {
+		{dataTypes: []string{"color"}, properties: []string{"outline-color"}},
+		{properties: []string{"outline-width"}},
+	},
+	"outline-offset": {{properties: []string{"outline-offset"}}},
+
+	// Effects
+	"opacity": {{properties: []string{"opacity"}}},
+
+	// Transforms
+	"origin":      {{properties: []string{"transform-origin"}}},
+	"perspective": {{properties: []string{"perspective"}}},
+
+	// Interactivity
+	"accent":      {{properties: []string{"accent-color"}}},
+	"caret":       {{properties: []string{"caret-color"}}},
+	"cursor":      {{properties: []string{"cursor"}}},
+	"will-change": {{properties: []string{"will-change"}}},
+	"scroll-m":    {{properties: []string{"scroll-margin"}}},
+	"scroll-p":    {{properties: []string{"scroll-padding"}}},
+
+	// SVG and tables
+	"fill": {{properties: []string{"fill"}}},
+	"stroke": {
+		{dataTypes: []string{"color"}, properties: []string{"stroke"}},
+		{dataTypes: []string{"number", "length", "percentage"}, properties: []string{"stroke-width"}},
+		{properties: []string{"stroke"}},
+	},
+	"border-spacing": {{properties: []string{"border-spacing"}}},
+}
+
+func init() {
+	for root, properties := range radiusProperties {
+		arbitraryMappings[root] = []arbitraryMapping{{properties: properties}}
+	}
+}
+
+// borderMappings reads a border value as a colour or else as a width
+func borderMappings(sides ...string) []arbitraryMapping {
+	colors := make([]string, len(sides))
+	widths := make([]string, len(sides))
+	for i, side := range sides {
+		colors[i] = side + "-color"
+		widths[i] = side + "-width"
+	}
+	return []arbitraryMapping{
+		{dataTypes: []string{"color"}, properties: colors},
+		{properties: widths},
+	}
+}
+
+func (tm *TailwindMappings) initArbitraryMappings() {
+	for root, mappings := range arbitraryMappings {
+		mappings := mappings
+		tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+			return arbitraryDeclarations(u, mappings)
+		})
+	}
+}
+
+// arbitraryDeclarations sets the properties of the first mapping that
+// accepts the utility's bracketed value
+func arbitraryDeclarations(u *parser.ParsedUtility, mappings []arbitraryMapping) []CSSProperty {
+	value := arbitraryValue(u)
+	if value == "" {
+		return nil
+	}
+
+	// An untyped value goes to the root's colour, if it has one
+	dataType := arbitraryType(u)
+	if dataType == "" {
+		for _, mapping := range mappings {
+			if containsString(mapping.dataTypes, "color") {
+				dataType = "color"
+			}
+		}
+	}
+
+	for _, mapping := range mappings {
+		if len(mapping.dataTypes) > 0 && !containsString(mapping.dataTypes, dataType) {
+			continue
+		}
+		props := make([]CSSProperty, 0, len(mapping.properties))
+		for _, property := range mapping.properties {
+			props = append(props, CSSProperty{Name: property, Value: value})
+		}
+		return props
+	}
+	return nil
+}
+
+// arbitraryValue returns the bracketed value of a utility like w-[37px], or ""
+func arbitraryValue(u *parser.ParsedUtility) string {
+	if u.Value == nil || u.Value.Kind != parser.ArbitraryValue || u.Negative || u.Modifier != nil {
+		return ""
+	}
+	return u.Value.Text
+}
+
+// arbitraryType returns the type hint of a bracketed value, or the type
+// inferred from it. An untyped value, like var(--x), is read as a colour by
+// the roots that take one, as v4 does: text-(--brand) sets color.
+func arbitraryType(u *parser.ParsedUtility) string {
+	if u.Value.DataType != "" {
+		return u.Value.DataType
+	}
+	return inferDataType(u.Value.Text)
+}
+
+// inferDataType guesses the type of an arbitrary value without a type hint,
+// returning "" when the value could be anything, like var(--x)
+func inferDataType(value string) string {
+	lower := strings.ToLower(value)
+	switch {
+	case lineWidthKeywords[lower]:
+		return "line-width"
+	case absoluteSizeKeywords[lower]:
+		return "absolute-size"
+	case lower == "larger" || lower == "smaller":
+		return "relative-size"
+	case strings.HasPrefix(value, "#") || colorKeywords[value]:
+		return "color"
+	case hasPrefixAny(lower, colorFunctions):
+		return "color"
+	case strings.HasPrefix(lower, "url("):
+		return "url"
+	case strings.Contains(lower, "gradient(") || strings.HasPrefix(lower, "image-set("):
+		return "image"
+	case signedNumber.MatchString(value):
+		return "number"
+	case percentagePattern.MatchString(value):
+		return "percentage"
+	case lengthPattern.MatchString(lower):
+		return "length"
+	case anglePattern.MatchString(lower):
+		return "angle"
+	case hasPrefixAny(lower, []string{"calc(", "min(", "max(", "clamp("}):
+		return "length"
+	case value == "cover" || value == "contain":
+		return "bg-size"
+	case isPosition(value):
+		return "position"
+	}
+	return ""
+}
+
+// isPosition reports whether value reads like center or left_1rem_top
+func isPosition(value string) bool {
+	keyword := false
+	for _, part := range strings.Fields(value) {
+		switch {
+		case positionKeywords[part]:
+			keyword = true
+		case lengthPattern.MatchString(part), percentagePattern.MatchString(part):
+		default:
+			return false
+		}
+	}
+	return keyword
+}
+
+func hasPrefixAny(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func setOf(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArbitraryValueTypes(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string
+	}{
+		{"text-(--brand)", "color: var(--brand)"},
+		{"text-[var(--x)]", "color: var(--x)"},
+		{"text-(length:--z)", "font-size: var(--z)"},
+		{"text-[larger]", "font-size: larger"},
+		{"border-(--line)", "border-color: var(--line)"},
+		{"border-[3px]", "border-width: 3px"},
+		{"border-[thin]", "border-width: thin"},
+		{"outline-[var(--x)]", "outline-color: var(--x)"},
+		{"decoration-(--x)", "text-decoration-color: var(--x)"},
+		{"bg-[50%]", "background-position: 50%"},
+		{"bg-[center_top]", "background-position: center top"},
+		{"bg-[length:50%]", "background-size: 50%"},
+		{"bg-[url(/a.png)]", "background-image: url(/a.png)"},
+		{"ring-(--r)", "--tw-ring-color: var(--r)"},
+		{"ring-offset-(--o)", "--tw-ring-offset-color: var(--o)"},
+		{"shadow-(--s)", "--tw-shadow: var(--s)"},
+	}
+
+	tm := NewTailwindMappings(DefaultOptions())
+	for _, tt := range tests {
+		got := declarations(t, tm, tt.class)
+		if len(got) == 0 || got[0] != tt.want {
+			t.Errorf("%s = %q, want first declaration %q", tt.class, got, tt.want)
+		}
+	}
+}
+
+func TestArbitraryFontSizeLineHeight(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string // Declarations joined by "; ", empty when the class does not convert
+	}{
+		{"text-[14px]/6", "font-size: 14px; line-height: 1.5rem"},
+		{"text-[14px]/[1.1]", "font-size: 14px; line-height: 1.1"},
+		{"text-[larger]/tight", "font-size: larger; line-height: 1.25"},
+		{"text-(length:--size)/7", "font-size: var(--size); line-height: 1.75rem"},
+		{"text-[length:var(--size)]/snug", "font-size: var(--size); line-height: 1.375"},
+		{"text-[14px]/bogus", ""},
+		{"text-[14px]", "font-size: 14px"},
+		// Untyped variables and colours take the modifier as an opacity
+		{"text-(--brand)/50", "color: color-mix(in oklab, var(--brand) 50%, transparent)"},
+		{"text-[#f00]/50", "color: color-mix(in oklab, #f00 50%, transparent)"},
+	}
+
+	tm := NewTailwindMappings(DefaultOptions())
+	for _, tt := range tests {
+		if got := strings.Join(declarations(t, tm, tt.class), "; "); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.class, got, tt.want)
+		}
+	}
+}
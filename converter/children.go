@@ -0,0 +1,115 @@
+package converter
+
+import (
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// childSelector is where the utilities styling an element's children put
+// their declarations, matching Tailwind v4's output
+const childSelector = ":where(& > :not(:last-child))"
+
+var spaceScale = lengthScale{negative: true, keywords: map[string]string{"px": "1px"}}
+
+// Border styles accepted by divide-*
+var borderStyles = setOf("solid", "dashed", "dotted", "double", "hidden", "none")
+
+// initChildMappings registers space-* and divide-*, which style the children
+// of the element through childSelector
+func (tm *TailwindMappings) initChildMappings() {
+	// Space between children, through margins reversible with space-x-reverse.
+	// The --tw-*-reverse variables are registered with an initial value of 0,
+	// so the reverse utilities apply whatever the class order.
+	for _, axis := range []string{"x", "y"} {
+		root := "space-" + axis
+		reverse := "--tw-space-" + axis + "-reverse"
+		start, end := "margin-inline-start", "margin-inline-end"
+		if axis == "y" {
+			start, end = "margin-block-start", "margin-block-end"
+		}
+
+		tm.selectors[root] = childSelector
+		tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+			if namedValue(u) == "reverse" {
+				return []CSSProperty{{Name: reverse, Value: "1"}}
+			}
+			value := tm.resolveLength(u, spaceScale)
+			if value == "" {
+				return nil
+			}
+			return []CSSProperty{
+				{Name: start, Value: "calc(" + value + " * var(" + reverse + "))"},
+				{Name: end, Value: "calc(" + value + " * calc(1 - var(" + reverse + ")))"},
+			}
+		})
+	}
+
+	// Borders between children, divide-y, divide-x-2 and divide-y-reverse
+	for _, axis := range []string{"x", "y"} {
+		root := "divide-" + axis
+		reverse := "--tw-divide-" + axis + "-reverse"
+		start, end := "border-inline-start", "border-inline-end"
+		if axis == "y" {
+			start, end = "border-top", "border-bottom"
+		}
+
+		tm.selectors[root] = childSelector
+		tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+			if namedValue(u) == "reverse" {
+				return []CSSProperty{{Name: reverse, Value: "1"}}
+			}
+			width := borderWidth(u)
+			if width == "" {
+				return nil
+			}
+			return []CSSProperty{
+				{Name: start + "-style", Value: "var(--tw-border-style)"},
+				{Name: end + "-style", Value: "var(--tw-border-style)"},
+				{Name: start + "-width", Value: "calc(" + width + " * var(" + reverse + "))"},
+				{Name: end + "-width", Value: "calc(" + width + " * calc(1 - var(" + reverse + ")))"},
+			}
+		})
+	}
+
+	// Divider colour and style, divide-gray-200 and divide-dashed
+	tm.selectors["divide"] = childSelector
+	tm.addDynamic("divide", func(u *parser.ParsedUtility) []CSSProperty {
+		if style := namedValue(u); borderStyles[style] {
+			return []CSSProperty{
+				{Name: "--tw-border-style", Value: style},
+				{Name: "border-style", Value: style},
+			}
+		}
+		colors, ok := tm.utilityColor(u)
+		if !ok {
+			return nil
+		}
+		var props []CSSProperty
+		for _, color := range colors {
+			props = append(props, CSSProperty{Name: "border-color", Value: color})
+		}
+		return props
+	})
+}
+
+// borderWidth returns the width of a border utility: 1px without a value,
+// Npx for a number, or an arbitrary length
+func borderWidth(u *parser.ParsedUtility) string {
+	switch {
+	case u.Negative || u.Modifier != nil:
+		return ""
+	case u.Value == nil:
+		return "1px"
+	case u.Value.Kind == parser.ArbitraryValue:
+		dataType := u.Value.DataType
+		if dataType == "" {
+			dataType = inferDataType(u.Value.Text)
+		}
+		if dataType == "length" || dataType == "line-width" || dataType == "" {
+			return u.Value.Text
+		}
+		return ""
+	case numberPattern.MatchString(u.Value.Text):
+		return u.Value.Text + "px"
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChildUtilities(t *testing.T) {
+	tests := []struct {
+		classes string
+		want    string // Declarations of the children rule, joined by "; "
+	}{
+		{"space-x-4", "margin-inline-start: calc(1rem * var(--tw-space-x-reverse)); margin-inline-end: calc(1rem * calc(1 - var(--tw-space-x-reverse)))"},
+		{"space-y-2", "margin-block-start: calc(0.5rem * var(--tw-space-y-reverse)); margin-block-end: calc(0.5rem * calc(1 - var(--tw-space-y-reverse)))"},
+		{"-space-x-2", "margin-inline-start: calc(-0.5rem * var(--tw-space-x-reverse)); margin-inline-end: calc(-0.5rem * calc(1 - var(--tw-space-x-reverse)))"},
+		{"space-x-px", "margin-inline-start: calc(1px * var(--tw-space-x-reverse)); margin-inline-end: calc(1px * calc(1 - var(--tw-space-x-reverse)))"},
+		{"space-y-reverse", "--tw-space-y-reverse: 1"},
+		{"space-x-reverse -space-x-1", "margin-inline-start: calc(-0.25rem * var(--tw-space-x-reverse)); margin-inline-end: calc(-0.25rem * calc(1 - var(--tw-space-x-reverse))); --tw-space-x-reverse: 1"},
+		{"divide-y", "border-top-style: var(--tw-border-style); border-bottom-style: var(--tw-border-style); border-top-width: calc(1px * var(--tw-divide-y-reverse)); border-bottom-width: calc(1px * calc(1 - var(--tw-divide-y-reverse)))"},
+		{"divide-x-2", "border-inline-start-style: var(--tw-border-style); border-inline-end-style: var(--tw-border-style); border-inline-start-width: calc(2px * var(--tw-divide-x-reverse)); border-inline-end-width: calc(2px * calc(1 - var(--tw-divide-x-reverse)))"},
+		{"divide-x-reverse", "--tw-divide-x-reverse: 1"},
+		{"divide-dashed", "--tw-border-style: dashed; border-style: dashed"},
+		{"divide-gray-200", "border-color: oklch(92.8% 0.006 264.531)"},
+	}
+
+	for _, tt := range tests {
+		rules := convert(t, tt.classes)
+		if len(rules) == 0 || rules[0].Selector != ":where(.div_1 > :not(:last-child))" {
+			t.Errorf("%s: got %+v, want a rule for the children", tt.classes, rules)
+			continue
+		}
+		if got := strings.ReplaceAll(ruleText(rules[0]), "\n", "; "); got != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.classes, got, tt.want)
+		}
+	}
+}
+
+func TestChildUtilitiesRegisterReverse(t *testing.T) {
+	tests := []struct {
+		classes    string
+		properties []string
+	}{
+		{"space-x-4", []string{"@property --tw-space-x-reverse"}},
+		{"space-y-2 space-y-reverse", []string{"@property --tw-space-y-reverse"}},
+		{"divide-y", []string{"@property --tw-border-style", "@property --tw-divide-y-reverse"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, rule := range convert(t, tt.classes) {
+			if strings.HasPrefix(rule.Selector, "@property") {
+				got = append(got, rule.Selector)
+			}
+		}
+		if strings.Join(got, ", ") != strings.Join(tt.properties, ", ") {
+			t.Errorf("%s: @property rules %q, want %q", tt.classes, got, tt.properties)
+		}
+	}
+}
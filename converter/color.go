@@ -0,0 +1,223 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ColorFormat selects how theme colours are written to the generated CSS
+type ColorFormat string
+
+const (
+	ColorOKLCH ColorFormat = "oklch" // As declared in the theme, OKLCH for the default palette
+	ColorHex   ColorFormat = "hex"   // #rrggbb, for older browser targets
+	ColorRGB   ColorFormat = "rgb"   // rgb(r, g, b), for older browser targets
+	ColorVar   ColorFormat = "var"   // var(--color-*) references to the theme variables
+)
+
+// ParseColorFormat validates a colour format name given on the command line
+func ParseColorFormat(name string) (ColorFormat, error) {
+	switch format := ColorFormat(strings.ToLower(name)); format {
+	case ColorOKLCH, ColorHex, ColorRGB, ColorVar:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown colour format %q (want oklch, hex, rgb or var)", name)
+}
+
+// Colour keywords every colour utility accepts, e.g. bg-transparent
+var specialColors = map[string]string{
+	"inherit":     "inherit",
+	"current":     "currentcolor",
+	"transparent": "transparent",
+}
+
+// rgba is a colour in sRGB with channels and alpha between 0 and 1
+type rgba struct {
+	r, g, b, a float64
+}
+
+// formatColor writes a theme colour value in the given format. Values it
+// cannot parse, like color-mix(...), are kept as they are.
+func formatColor(value string, format ColorFormat) string {
+	if format != ColorHex && format != ColorRGB {
+		return value
+	}
+
+	color, ok := parseColor(value)
+	if !ok {
+		return value
+	}
+	if format == ColorHex {
+		return color.hex()
+	}
+	return color.rgb()
+}
+
+// parseColor reads hex, rgb() and oklch() colours
+func parseColor(value string) (rgba, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch {
+	case strings.HasPrefix(value, "#"):
+		return parseHexColor(value[1:])
+	case strings.HasPrefix(value, "rgb(") || strings.HasPrefix(value, "rgba("):
+		args, ok := colorArguments(value)
+		if !ok || len(args) < 3 {
+			return rgba{}, false
+		}
+		var channels [3]float64
+		for i := range channels {
+			channel, ok := parseChannel(args[i], 255, 1)
+			if !ok {
+				return rgba{}, false
+			}
+			channels[i] = channel
+		}
+		alpha, ok := parseAlpha(args[3:])
+		return rgba{channels[0], channels[1], channels[2], alpha}, ok
+	case strings.HasPrefix(value, "oklch("):
+		args, ok := colorArguments(value)
+		if !ok || len(args) < 3 {
+			return rgba{}, false
+		}
+		l, okL := parseChannel(args[0], 1, 1)
+		c, okC := parseChannel(args[1], 1, 0.4)
+		h, okH := strconv.ParseFloat(strings.TrimSuffix(args[2], "deg"), 64)
+		alpha, okA := parseAlpha(args[3:])
+		if !okL || !okC || okH != nil || !okA {
+			return rgba{}, false
+		}
+		color := oklchToSRGB(l, c, h)
+		color.a = alpha
+		return color, true
+	}
+	return rgba{}, false
+}
+
+func parseHexColor(hex string) (rgba, bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		var expanded strings.Builder
+		for _, digit := range hex {
+			expanded.WriteRune(digit)
+			expanded.WriteRune(digit)
+		}
+		hex = expanded.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return rgba{}, false
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgba{}, false
+	}
+	return rgba{
+		r: float64(n>>24&0xff) / 255,
+		g: float64(n>>16&0xff) / 255,
+		b: float64(n>>8&0xff) / 255,
+		a: float64(n&0xff) / 255,
+	}, true
+}
+
+// colorArguments splits the arguments of a colour function, accepting both
+// "1 2 3 / 0.5" and "1, 2, 3, 0.5". An alpha comes back as the fourth.
+func colorArguments(value string) ([]string, bool) {
+	open := strings.IndexByte(value, '(')
+	if open < 0 || !strings.HasSuffix(value, ")") {
+		return nil, false
+	}
+	inner := value[open+1 : len(value)-1]
+	if strings.Contains(inner, "(") {
+		return nil, false // var(), calc() and relative colours are left alone
+	}
+
+	inner = strings.NewReplacer(",", " ", "/", " ").Replace(inner)
+	return strings.Fields(inner), true
+}
+
+// parseChannel reads a channel as a number divided by numberScale, or as a
+// percentage of percentScale
+func parseChannel(text string, numberScale, percentScale float64) (float64, bool) {
+	if strings.HasSuffix(text, "%") {
+		n, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+		return n / 100 * percentScale, err == nil
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	return n / numberScale, err == nil
+}
+
+func parseAlpha(args []string) (float64, bool) {
+	if len(args) == 0 {
+		return 1, true
+	}
+	if len(args) > 1 {
+		return 0, false
+	}
+	if strings.HasSuffix(args[0], "%") {
+		n, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "%"), 64)
+		return n / 100, err == nil
+	}
+	n, err := strconv.ParseFloat(args[0], 64)
+	return n, err == nil
+}
+
+// oklchToSRGB converts OKLCH to gamma-encoded sRGB, clipped to the sRGB gamut
+func oklchToSRGB(l, c, h float64) rgba {
+	hue := h * math.Pi / 180
+	a := c * math.Cos(hue)
+	b := c * math.Sin(hue)
+
+	lp := l + 0.3963377774*a + 0.2158037573*b
+	mp := l - 0.1055613458*a - 0.0638541728*b
+	sp := l - 0.0894841775*a - 1.2914855480*b
+	lc, mc, sc := lp*lp*lp, mp*mp*mp, sp*sp*sp
+
+	return rgba{
+		r: gammaEncode(4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc),
+		g: gammaEncode(-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc),
+		b: gammaEncode(-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc),
+		a: 1,
+	}
+}
+
+func gammaEncode(linear float64) float64 {
+	var encoded float64
+	if linear <= 0.0031308 {
+		encoded = 12.92 * linear
+	} else {
+		encoded = 1.055*math.Pow(linear, 1/2.4) - 0.055
+	}
+	return math.Max(0, math.Min(1, encoded))
+}
+
+func (c rgba) bytes() (int, int, int) {
+	return int(math.Round(c.r * 255)), int(math.Round(c.g * 255)), int(math.Round(c.b * 255))
+}
+
+func (c rgba) hex() string {
+	r, g, b := c.bytes()
+	if c.a < 1 {
+		return fmt.Sprintf("#%02x%02x%02x%02x", r, g, b, int(math.Round(c.a*255)))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// rgba writes the colour in the legacy rgba() syntax, alpha included
+func (c rgba) rgba() string {
+	r, g, b := c.bytes()
+	alpha := math.Round(c.a*1000) / 1000
+	return fmt.Sprintf("rgba(%d, %d, %d, %s)", r, g, b, strconv.FormatFloat(alpha, 'f', -1, 64))
+}
+
+func (c rgba) rgb() string {
+	r, g, b := c.bytes()
+	if c.a < 1 {
+		return fmt.Sprintf("rgba(%d, %d, %d, %s)", r, g, b, strconv.FormatFloat(c.a, 'f', -1, 64))
+	}
+	return fmt.Sprintf("rgb(%d, %d, %d)", r, g, b)
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpacityModifiers(t *testing.T) {
+	tests := []struct {
+		classes  string
+		format   ColorFormat
+		fallback bool
+		want     string
+	}{
+		{
+			classes: "bg-red-500/50",
+			format:  ColorOKLCH,
+			want:    "{ .div_1 } background-color: color-mix(in oklab, oklch(63.7% 0.237 25.331) 50%, transparent)",
+		},
+		{
+			classes: "text-black/[.35]",
+			format:  ColorOKLCH,
+			want:    "{ .div_1 } color: color-mix(in oklab, #000 35%, transparent)",
+		},
+		{
+			classes: "border-blue-500/(--alpha)",
+			format:  ColorHex,
+			want:    "{ .div_1 } border-color: color-mix(in oklab, #2b7fff var(--alpha), transparent)",
+		},
+		{
+			classes: "bg-[#ff0000]/25 text-current/10",
+			format:  ColorOKLCH,
+			want:    "{ .div_1 } background-color: color-mix(in oklab, #ff0000 25%, transparent); color: color-mix(in oklab, currentcolor 10%, transparent)",
+		},
+		{
+			classes: "bg-red-500/50",
+			format:  ColorVar,
+			want:    "{ .div_1 } background-color: color-mix(in oklab, var(--color-red-500) 50%, transparent)",
+		},
+		{
+			classes:  "bg-red-500/50 text-black/[.35]",
+			format:   ColorOKLCH,
+			fallback: true,
+			want: "{ .div_1 } background-color: rgba(251, 44, 54, 0.5); background-color: color-mix(in oklab, oklch(63.7% 0.237 25.331) 50%, transparent); " +
+				"color: rgba(0, 0, 0, 0.35); color: color-mix(in oklab, #000 35%, transparent)",
+		},
+		{
+			// A variable alpha cannot be precomputed
+			classes:  "border-blue-500/(--alpha)",
+			format:   ColorOKLCH,
+			fallback: true,
+			want:     "{ .div_1 } border-color: color-mix(in oklab, oklch(62.3% 0.214 259.815) var(--alpha), transparent)",
+		},
+	}
+
+	for _, tt := range tests {
+		options := DefaultOptions()
+		options.ColorFormat = tt.format
+		options.ColorFallback = tt.fallback
+		got, _ := render(convertWith(t, options, tt.classes))
+		if strings.Join(got, "\n") != tt.want {
+			t.Errorf("%s (%s, fallback %v):\n%s\nwant:\n%s", tt.classes, tt.format, tt.fallback, strings.Join(got, "\n"), tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package converter
+
+import "tailwind-v4-to-css-converter/internal/parser"
+
+func (tm *TailwindMappings) initCompositeMappings() {
+	// Screen reader only content, and its undo for e.g. focus:not-sr-only
+	tm.staticMappings["sr-only"] = []CSSProperty{
+		{Name: "position", Value: "absolute"},
+		{Name: "width", Value: "1px"},
+		{Name: "height", Value: "1px"},
+		{Name: "padding", Value: "0"},
+		{Name: "margin", Value: "-1px"},
+		{Name: "overflow", Value: "hidden"},
+		{Name: "clip", Value: "rect(0, 0, 0, 0)"},
+		{Name: "white-space", Value: "nowrap"},
+		{Name: "border-width", Value: "0"},
+	}
+	tm.staticMappings["not-sr-only"] = []CSSProperty{
+		{Name: "position", Value: "static"},
+		{Name: "width", Value: "auto"},
+		{Name: "height", Value: "auto"},
+		{Name: "padding", Value: "0"},
+		{Name: "margin", Value: "0"},
+		{Name: "overflow", Value: "visible"},
+		{Name: "clip", Value: "auto"},
+		{Name: "white-space", Value: "normal"},
+	}
+
+	// Text overflow
+	tm.staticMappings["truncate"] = []CSSProperty{
+		{Name: "overflow", Value: "hidden"},
+		{Name: "text-overflow", Value: "ellipsis"},
+		{Name: "white-space", Value: "nowrap"},
+	}
+	tm.staticMappings["text-ellipsis"] = []CSSProperty{{Name: "text-overflow", Value: "ellipsis"}}
+	tm.staticMappings["text-clip"] = []CSSProperty{{Name: "text-overflow", Value: "clip"}}
+
+	// Line clamping, line-clamp-3
+	tm.staticMappings["line-clamp-none"] = []CSSProperty{
+		{Name: "overflow", Value: "visible"},
+		{Name: "display", Value: "block"},
+		{Name: "-webkit-box-orient", Value: "horizontal"},
+		{Name: "-webkit-line-clamp", Value: "unset"},
+	}
+	tm.addDynamic("line-clamp", func(u *parser.ParsedUtility) []CSSProperty {
+		lines := numericValue(u)
+		if lines == "" {
+			lines = arbitraryValue(u)
+		}
+		if lines == "" {
+			return nil
+		}
+		return []CSSProperty{
+			{Name: "overflow", Value: "hidden"},
+			{Name: "display", Value: "-webkit-box"},
+			{Name: "-webkit-box-orient", Value: "vertical"},
+			{Name: "-webkit-line-clamp", Value: lines},
+		}
+	})
+
+	// Word breaks and overflow wrapping
+	tm.staticMappings["break-normal"] = []CSSProperty{
+		{Name: "overflow-wrap", Value: "normal"},
+		{Name: "word-break", Value: "normal"},
+	}
+	tm.staticMappings["break-words"] = []CSSProperty{{Name: "overflow-wrap", Value: "break-word"}}
+	tm.staticMappings["break-all"] = []CSSProperty{{Name: "word-break", Value: "break-all"}}
+	tm.staticMappings["break-keep"] = []CSSProperty{{Name: "word-break", Value: "keep-all"}}
+	tm.staticMappings["wrap-break-word"] = []CSSProperty{{Name: "overflow-wrap", Value: "break-word"}}
+	tm.staticMappings["wrap-anywhere"] = []CSSProperty{{Name: "overflow-wrap", Value: "anywhere"}}
+	tm.staticMappings["wrap-normal"] = []CSSProperty{{Name: "overflow-wrap", Value: "normal"}}
+
+	// Container, full width up to the smallest breakpoint and capped at
+	// each breakpoint from there
+	tm.staticMappings["container"] = []CSSProperty{{Name: "width", Value: "100%"}}
+	tm.breakpointSteps["container"] = func(width string) []CSSProperty {
+		return []CSSProperty{{Name: "max-width", Value: width}}
+	}
+}
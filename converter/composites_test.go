@@ -0,0 +1,104 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompositeUtilities(t *testing.T) {
+	tests := []struct {
+		classes string
+		want    string
+	}{
+		{
+			classes: "sr-only",
+			want:    "{ .div_1 } position: absolute; width: 1px; height: 1px; padding: 0; margin: -1px; overflow: hidden; clip: rect(0, 0, 0, 0); white-space: nowrap; border-width: 0",
+		},
+		{
+			classes: "not-sr-only",
+			want:    "{ .div_1 } position: static; width: auto; height: auto; padding: 0; margin: 0; overflow: visible; clip: auto; white-space: normal",
+		},
+		{
+			classes: "md:sr-only",
+			want:    "@media (width >= 48rem) { .div_1 } position: absolute; width: 1px; height: 1px; padding: 0; margin: -1px; overflow: hidden; clip: rect(0, 0, 0, 0); white-space: nowrap; border-width: 0",
+		},
+		{
+			classes: "truncate",
+			want:    "{ .div_1 } overflow: hidden; text-overflow: ellipsis; white-space: nowrap",
+		},
+		{
+			classes: "line-clamp-3",
+			want:    "{ .div_1 } overflow: hidden; display: -webkit-box; -webkit-box-orient: vertical; -webkit-line-clamp: 3",
+		},
+		{
+			classes: "line-clamp-none",
+			want:    "{ .div_1 } overflow: visible; display: block; -webkit-box-orient: horizontal; -webkit-line-clamp: unset",
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, []string{tt.want}, nil)
+	}
+}
+
+func TestContainerBreakpoints(t *testing.T) {
+	tests := []struct {
+		classes string
+		want    []string
+	}{
+		{
+			classes: "container",
+			want: []string{
+				"{ .div_1 } width: 100%",
+				"@media (width >= 40rem) { .div_1 } max-width: 40rem",
+				"@media (width >= 48rem) { .div_1 } max-width: 48rem",
+				"@media (width >= 64rem) { .div_1 } max-width: 64rem",
+				"@media (width >= 80rem) { .div_1 } max-width: 80rem",
+				"@media (width >= 96rem) { .div_1 } max-width: 96rem",
+			},
+		},
+		{
+			classes: "container mx-auto px-4",
+			want: []string{
+				"{ .div_1 } margin-left: auto; margin-right: auto; width: 100%; padding-left: 1rem; padding-right: 1rem",
+				"@media (width >= 40rem) { .div_1 } max-width: 40rem",
+				"@media (width >= 48rem) { .div_1 } max-width: 48rem",
+				"@media (width >= 64rem) { .div_1 } max-width: 64rem",
+				"@media (width >= 80rem) { .div_1 } max-width: 80rem",
+				"@media (width >= 96rem) { .div_1 } max-width: 96rem",
+			},
+		},
+		{
+			classes: "hover:container",
+			want: []string{
+				"@media (hover: hover) { .div_1:hover } width: 100%",
+				"@media (hover: hover) @media (width >= 40rem) { .div_1:hover } max-width: 40rem",
+				"@media (hover: hover) @media (width >= 48rem) { .div_1:hover } max-width: 48rem",
+				"@media (hover: hover) @media (width >= 64rem) { .div_1:hover } max-width: 64rem",
+				"@media (hover: hover) @media (width >= 80rem) { .div_1:hover } max-width: 80rem",
+				"@media (hover: hover) @media (width >= 96rem) { .div_1:hover } max-width: 96rem",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, tt.want, nil)
+	}
+}
+
+func TestContainerCustomBreakpoints(t *testing.T) {
+	options := DefaultOptions()
+	if err := options.Theme.Parse("@theme { --breakpoint-*: initial; --breakpoint-tablet: 600px; --breakpoint-desktop: 1200px; }"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := render(convertWith(t, options, "container"))
+	want := []string{
+		"{ .div_1 } width: 100%",
+		"@media (width >= 600px) { .div_1 } max-width: 600px",
+		"@media (width >= 1200px) { .div_1 } max-width: 1200px",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -1,7 +1,9 @@
 package converter
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"tailwind-v4-to-css-converter/internal/parser"
 )
@@ -9,10 +11,22 @@ import (
 type Converter struct {
 	mappings     *TailwindMappings
 	modern       *ModernFeatures
+	variants     *VariantEngine
 	classCounter int
+	diagnostics  []Diagnostic
+	markers      map[int]*elementMarkers // By element ID, for group-* and peer-*
+	parents      map[int]int             // Parent element IDs, -1 at the top level
+}
+
+// elementMarkers are the semantic classes of an element carrying group or
+// peer marker classes, keyed by group name, "" for a plain group or peer
+type elementMarkers struct {
+	groups map[string]string
+	peers  map[string]string
 }
 
 type CSSRule struct {
+	AtRules    []string // Enclosing at-rules, outermost first
 	Selector   string
 	Properties []CSSProperty
 }
@@ -22,110 +36,414 @@ type CSSProperty struct {
 	Value string
 }
 
+// Diagnostic reports a class that could not be converted faithfully
+type Diagnostic struct {
+	Class   string
+	Path    string // DOM path of the element carrying the class
+	Line    int
+	Column  int
+	Message string
+}
+
 type SemanticMapping struct {
 	OriginalClasses string
 	SemanticName    string
+	Condition       string            // Condition under which the classes apply, empty if always
+	Refs            []parser.ClassRef // Class strings to rewrite, in source order
+}
+
+// Options configure how classes are converted
+type Options struct {
+	Theme         *parser.Theme // Design tokens, the Tailwind defaults unless a theme file is given
+	ColorFormat   ColorFormat   // How theme colours are written
+	ColorFallback bool          // Precede color-mix() with a precomputed rgba() for older browsers
+	DarkMode      string        // DarkModeMedia, or a selector such as ".dark &" for dark:
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Theme:       DefaultTheme(),
+		ColorFormat: ColorOKLCH,
+		DarkMode:    DarkModeMedia,
+	}
 }
 
 func NewConverter() *Converter {
+	return NewConverterWithOptions(DefaultOptions())
+}
+
+func NewConverterWithOptions(options Options) *Converter {
+	if options.Theme == nil {
+		options.Theme = DefaultTheme()
+	}
+
 	return &Converter{
-		mappings:     NewTailwindMappings(),
+		mappings:     NewTailwindMappings(options),
 		modern:       NewModernFeatures(),
+		variants:     NewVariantEngine(options),
 		classCounter: 0,
 	}
 }
 
-func (c *Converter) Convert(classes []parser.ExtractedClass) ([]CSSRule, []SemanticMapping) {
+// Convert turns each element's class group into its own semantic class. Rules
+// and mappings come back in source order.
+func (c *Converter) Convert(groups []parser.ClassGroup) ([]CSSRule, []SemanticMapping) {
 	var cssRules []CSSRule
 	var semanticMappings []SemanticMapping
 
-	// Group classes by element and create consolidated semantic classes
-	elementGroups := c.groupByElement(classes)
-
-	for element, elementClasses := range elementGroups {
+	// Name every group first, so group-* and peer-* variants can refer to
+	// the semantic classes of marked elements
+	c.markers = make(map[int]*elementMarkers)
+	c.parents = make(map[int]int)
+	semanticNames := make([]string, len(groups))
+	for i, group := range groups {
 		// Create semantic class name
-		semanticName := c.generateSemanticName(element, elementClasses)
+		element := group.Element
+		if group.Condition != "" {
+			element += "_" + conditionSlug(group.Condition)
+		}
+		semanticNames[i] = c.generateSemanticName(element, group.Classes)
+		c.recordMarkers(group, semanticNames[i])
+	}
 
-		// Convert classes to CSS properties and deduplicate
-		properties := c.convertAndDeduplicateProperties(elementClasses)
+	for i, group := range groups {
+		semanticName := semanticNames[i]
 
-		if len(properties) > 0 {
-			cssRules = append(cssRules, CSSRule{
-				Selector:   "." + semanticName,
-				Properties: properties,
-			})
+		// Convert classes to CSS rules, one per variant scope
+		rules, convertedClasses := c.convertClasses(semanticName, group)
+		cssRules = append(cssRules, rules...)
 
-			// Create mapping with all original classes for this element
-			var originalClassNames []string
-			for _, class := range elementClasses {
-				originalClassNames = append(originalClassNames, class.Name)
-			}
+		if len(convertedClasses) > 0 {
+			// Create mapping with the classes the semantic class replaces;
+			// classes that could not be converted stay in the markup
+			originalClassNames := convertedClasses
 
 			semanticMappings = append(semanticMappings, SemanticMapping{
 				OriginalClasses: strings.Join(originalClassNames, " "),
 				SemanticName:    semanticName,
+				Condition:       group.Condition,
+				Refs:            group.Refs,
 			})
 		}
 	}
 
+	// Add the @keyframes of the animations used, and register the --tw-*
+	// variables the rules rely on
+	cssRules = append(cssRules, c.mappings.keyframeRules(cssRules)...)
+	cssRules = append(cssRules, propertyRules(cssRules)...)
+
 	return cssRules, semanticMappings
 }
 
-func (c *Converter) groupByElement(classes []parser.ExtractedClass) map[string][]parser.ExtractedClass {
-	groups := make(map[string][]parser.ExtractedClass)
+// Maps reports whether the converter handles a utility: it converts, marks
+// an element for group-* and peer-* variants, or names a colour the theme
+// lacks, which is reported instead of being left in the markup
+func (c *Converter) Maps(u *parser.ParsedUtility) bool {
+	if isMarker(u) || len(c.mappings.Convert(u)) > 0 || len(c.modern.Convert(u)) > 0 {
+		return true
+	}
+	_, unknown := c.mappings.UnknownColor(u)
+	return unknown
+}
 
-	for _, class := range classes {
-		// Use element as the key for grouping
-		groups[class.Context] = append(groups[class.Context], class)
+// isMarker reports whether a class is a group or peer marker, like group/card
+func isMarker(u *parser.ParsedUtility) bool {
+	return (u.Root == "group" || u.Root == "peer") && u.Value == nil && len(u.Variants) == 0 && !u.Important && !u.Negative
+}
+
+// recordMarkers remembers the group and peer markers of a class group under
+// its semantic class
+func (c *Converter) recordMarkers(group parser.ClassGroup, semanticName string) {
+	parent := -1
+	if len(group.Ancestors) > 0 {
+		parent = group.Ancestors[0]
 	}
+	c.parents[group.ElementID] = parent
 
-	return groups
+	for _, class := range group.Classes {
+		u := class.Utility
+		if !isMarker(u) {
+			continue
+		}
+		markers := c.markers[group.ElementID]
+		if markers == nil {
+			markers = &elementMarkers{groups: make(map[string]string), peers: make(map[string]string)}
+			c.markers[group.ElementID] = markers
+		}
+
+		name := ""
+		if u.Modifier != nil {
+			name = u.Modifier.Text
+		}
+		elements := markers.groups
+		if u.Root == "peer" {
+			elements = markers.peers
+		}
+		if _, exists := elements[name]; !exists {
+			elements[name] = "." + semanticName
+		}
+	}
 }
 
-func (c *Converter) convertAndDeduplicateProperties(classes []parser.ExtractedClass) []CSSProperty {
-	propertyMap := make(map[string]CSSProperty)
-	var unknownClasses []string
+// markersFor finds the marked elements a class group's group-* and peer-*
+// variants refer to: the nearest ancestor for each group name, and the
+// nearest preceding sibling for each peer name
+func (c *Converter) markersFor(group parser.ClassGroup) Markers {
+	markers := Markers{Groups: make(map[string]string), Peers: make(map[string]string)}
 
-	for _, class := range classes {
-		// Try to convert using mappings first
-		if cssProps := c.mappings.Convert(class.Name); len(cssProps) > 0 {
-			for _, prop := range cssProps {
-				// Use property name as key to deduplicate (later values override earlier ones)
-				propertyMap[prop.Name] = prop
+	for _, ancestor := range group.Ancestors {
+		if marked := c.markers[ancestor]; marked != nil {
+			for name, selector := range marked.groups {
+				if _, exists := markers.Groups[name]; !exists {
+					markers.Groups[name] = selector
+				}
 			}
-		} else if modernProps := c.modern.Convert(class.Name); len(modernProps) > 0 {
-			for _, prop := range modernProps {
-				propertyMap[prop.Name] = prop
+		}
+	}
+
+	parent := c.parents[group.ElementID]
+	nearest := make(map[string]int)
+	for id, marked := range c.markers {
+		if id >= group.ElementID || c.parents[id] != parent {
+			continue
+		}
+		for name, selector := range marked.peers {
+			if previous, exists := nearest[name]; !exists || id > previous {
+				nearest[name] = id
+				markers.Peers[name] = selector
 			}
-		} else {
-			// Collect unknown classes to add as comments
-			unknownClasses = append(unknownClasses, class.Name)
 		}
 	}
 
-	// Convert map back to slice
+	return markers
+}
+
+// Diagnostics returns the problems found by Convert so far
+func (c *Converter) Diagnostics() []Diagnostic {
+	return c.diagnostics
+}
+
+func (c *Converter) report(group parser.ClassGroup, class, message string) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Class:   class,
+		Path:    group.Path,
+		Line:    group.Line,
+		Column:  group.Column,
+		Message: message,
+	})
+}
+
+// conditionSlug turns a JavaScript condition into a short name fragment,
+// e.g. "!isOpen" becomes "not_is_open".
+func conditionSlug(condition string) string {
+	var words []string
+	if strings.HasPrefix(condition, "!") {
+		words = append(words, "not")
+	}
+
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range condition {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			flush()
+			word.WriteRune(r + ('a' - 'A'))
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	if len(words) > 4 {
+		words = words[:4]
+	}
+	if len(words) == 0 {
+		return "cond"
+	}
+	return strings.Join(words, "_")
+}
+
+// ruleBucket collects the declarations of one rule scope of an element
+type ruleBucket struct {
+	scope   RuleScope
+	classes [][]CSSProperty // Declarations of each class, in source order
+	unknown []string        // Classes that could not be converted
+}
+
+func (b *ruleBucket) add(props []CSSProperty) {
+	b.classes = append(b.classes, props)
+}
+
+// properties merges the classes' declarations after the scope's own. Classes
+// are ordered like Tailwind orders utilities, shorthands before longhands,
+// so the result does not depend on the order of the classes. A class drops
+// the properties a later class sets again, while the declarations of a
+// single class (e.g. fallbacks) are kept as given.
+func (b *ruleBucket) properties() []CSSProperty {
+	type sortedClass struct {
+		props []CSSProperty
+		sort  []int
+	}
+	classes := make([]sortedClass, len(b.classes))
+	for i, props := range b.classes {
+		classes[i] = sortedClass{props: props, sort: propertySort(props)}
+	}
+	sort.SliceStable(classes, func(i, j int) bool {
+		return propertyLess(classes[i].sort, classes[j].sort)
+	})
+
+	set := make(map[string]bool)
+	for i := len(classes) - 1; i >= 0; i-- {
+		var kept []CSSProperty
+		for _, prop := range classes[i].props {
+			if !set[prop.Name] {
+				kept = append(kept, prop)
+			}
+		}
+		for _, prop := range kept {
+			set[prop.Name] = true
+		}
+		classes[i].props = kept
+	}
+
 	var properties []CSSProperty
-	for _, prop := range propertyMap {
-		properties = append(properties, prop)
+	for _, prop := range b.scope.Declarations {
+		if !set[prop.Name] {
+			properties = append(properties, prop)
+		}
+	}
+	for _, class := range classes {
+		properties = append(properties, class.props...)
 	}
 
 	// Add unknown classes as comments
-	if len(unknownClasses) > 0 {
+	if len(b.unknown) > 0 {
 		properties = append(properties, CSSProperty{
 			Name:  "/* Unknown classes */",
-			Value: strings.Join(unknownClasses, " "),
+			Value: strings.Join(b.unknown, " "),
 		})
 	}
-
 	return properties
 }
 
+// convertClasses turns an element's classes into rules for the semantic class,
+// one per variant scope, ordered like Tailwind orders variants.
+func (c *Converter) convertClasses(semanticName string, group parser.ClassGroup) ([]CSSRule, []string) {
+	var buckets []*ruleBucket
+	bucketIndex := make(map[string]*ruleBucket)
+	var convertedClasses []string
+	var unknownClasses []string
+
+	bucketFor := func(scope RuleScope) *ruleBucket {
+		key := scope.Key()
+		if bucket, exists := bucketIndex[key]; exists {
+			return bucket
+		}
+		bucket := &ruleBucket{scope: scope}
+		bucketIndex[key] = bucket
+		buckets = append(buckets, bucket)
+		return bucket
+	}
+
+	add := func(u *parser.ParsedUtility, scope RuleScope, props []CSSProperty) {
+		if selector := c.mappings.Selector(u); selector != "&" {
+			scope.selector(selector)
+		}
+		if u.Important {
+			props = markImportant(props)
+		}
+		bucketFor(scope).add(props)
+	}
+
+	markers := c.markersFor(group)
+	for _, class := range group.Classes {
+		u := class.Utility
+
+		// Markers only name the element for group-* and peer-* variants
+		if isMarker(u) {
+			convertedClasses = append(convertedClasses, class.Name)
+			continue
+		}
+
+		// Try to convert using mappings first
+		cssProps := c.mappings.Convert(u)
+		if len(cssProps) == 0 {
+			cssProps = c.modern.Convert(u)
+		}
+
+		scope, err := c.variants.Apply(u.Variants, markers)
+		if len(cssProps) == 0 || err != nil {
+			// Collect unknown classes to add as comments
+			unknownClasses = append(unknownClasses, class.Name)
+			var missing *missingMarkerError
+			if color, unknown := c.mappings.UnknownColor(u); unknown {
+				c.report(group, class.Name, fmt.Sprintf("unknown colour %q", color))
+			} else if errors.As(err, &missing) {
+				c.report(group, class.Name, err.Error())
+			}
+			continue
+		}
+
+		add(u, scope, cssProps)
+		convertedClasses = append(convertedClasses, class.Name)
+
+		// Utilities like container change at every breakpoint, nested inside
+		// the class's own variants as in md:container
+		for _, step := range c.mappings.BreakpointSteps(u) {
+			variants := append(append([]parser.Variant(nil), u.Variants...), parser.Variant{Raw: step.Breakpoint, Name: step.Breakpoint})
+			if scope, err := c.variants.Apply(variants, markers); err == nil {
+				add(u, scope, step.Properties)
+			}
+		}
+	}
+
+	if len(unknownClasses) > 0 {
+		bucketFor(RuleScope{Selector: "&"}).unknown = unknownClasses
+	}
+
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].scope.Less(buckets[j].scope)
+	})
+
+	rules := make([]CSSRule, 0, len(buckets))
+	for _, bucket := range buckets {
+		rules = append(rules, CSSRule{
+			AtRules:    bucket.scope.AtRules,
+			Selector:   strings.ReplaceAll(bucket.scope.Selector, "&", "."+semanticName),
+			Properties: bucket.properties(),
+		})
+	}
+
+	return rules, convertedClasses
+}
+
+func markImportant(props []CSSProperty) []CSSProperty {
+	important := make([]CSSProperty, len(props))
+	for i, prop := range props {
+		important[i] = CSSProperty{Name: prop.Name, Value: prop.Value + " !important"}
+	}
+	return important
+}
+
 func (c *Converter) generateSemanticName(element string, classes []parser.ExtractedClass) string {
 	c.classCounter++
 
-	// Clean up element name
-	cleanElement := strings.ReplaceAll(element, ".", "_")
-	cleanElement = strings.ToLower(cleanElement)
+	// Clean up element name, e.g. my-widget, svg:rect or Foo.Bar, so the
+	// class is a valid identifier for styles.name
+	cleanElement := strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(element))
 
 	// Analyze classes to determine primary purpose
 	hasLayout := false
@@ -0,0 +1,171 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// convert converts the classes of a single div with the default options
+func convert(t *testing.T, classes string) []CSSRule {
+	t.Helper()
+	return convertWith(t, DefaultOptions(), classes)
+}
+
+// convertWith converts the classes of a single div
+func convertWith(t *testing.T, options Options, classes string) []CSSRule {
+	t.Helper()
+	group := parser.ClassGroup{Element: "div", Path: "div"}
+	for _, name := range strings.Fields(classes) {
+		u, err := parser.ParseUtility(name)
+		if err != nil {
+			t.Fatalf("ParseUtility(%q): %v", name, err)
+		}
+		group.Classes = append(group.Classes, parser.ExtractedClass{Name: name, Utility: u})
+	}
+	rules, _ := NewConverterWithOptions(options).Convert([]parser.ClassGroup{group})
+	return rules
+}
+
+// convertMarkup parses markup and converts the classes of all its elements
+// with the default options
+func convertMarkup(t *testing.T, content string) ([]CSSRule, []Diagnostic) {
+	t.Helper()
+	document, err := parser.NewHTMLParser().ParseContent(content)
+	if err != nil {
+		t.Fatalf("ParseContent: %v", err)
+	}
+	c := NewConverter()
+	rules, _ := c.Convert(parser.NewClassExtractor().Extract(document))
+	return rules, c.Diagnostics()
+}
+
+// declarations converts a single class with the default theme, rendering its
+// declarations as "property: value" lines
+func declarations(t *testing.T, tm *TailwindMappings, class string) []string {
+	t.Helper()
+	u, err := parser.ParseUtility(class)
+	if err != nil {
+		t.Fatalf("ParseUtility(%q): %v", class, err)
+	}
+	var lines []string
+	for _, prop := range tm.Convert(u) {
+		lines = append(lines, prop.Name+": "+prop.Value)
+	}
+	return lines
+}
+
+// ruleText renders a rule's declarations as "property: value" lines
+func ruleText(rule CSSRule) string {
+	var lines []string
+	for _, prop := range rule.Properties {
+		lines = append(lines, prop.Name+": "+prop.Value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// render renders rules as "at-rules { selector } declarations", listing the
+// names of the registered @property rules separately
+func render(rules []CSSRule) (rendered []string, properties []string) {
+	for _, rule := range rules {
+		if name := strings.TrimPrefix(rule.Selector, "@property "); name != rule.Selector {
+			properties = append(properties, name)
+			continue
+		}
+		scope := strings.TrimSpace(strings.Join(rule.AtRules, " ") + " { " + rule.Selector + " }")
+		rendered = append(rendered, scope+" "+strings.ReplaceAll(ruleText(rule), "\n", "; "))
+	}
+	return rendered, properties
+}
+
+// checkRender checks the rendered rules and registered properties of the
+// classes of a div; nil properties are not checked
+func checkRender(t *testing.T, classes string, want, properties []string) {
+	t.Helper()
+	got, registered := render(convert(t, classes))
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("%s:\n%s\nwant:\n%s", classes, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if properties != nil && strings.Join(registered, " ") != strings.Join(properties, " ") {
+		t.Errorf("%s: @property %q, want %q", classes, registered, properties)
+	}
+}
+
+func TestDeclarationOrder(t *testing.T) {
+	tests := []struct {
+		classes string
+		want    []string
+	}{
+		{"px-2 p-4", []string{"padding: 1rem", "padding-left: 0.5rem", "padding-right: 0.5rem"}},
+		{"p-4 px-2", []string{"padding: 1rem", "padding-left: 0.5rem", "padding-right: 0.5rem"}},
+		{"mt-2 m-4", []string{"margin: 1rem", "margin-top: 0.5rem"}},
+		{"border-red-500 border", []string{"border: 1px solid #e5e7eb", "border-color: oklch(63.7% 0.237 25.331)"}},
+		{"p-2 p-4", []string{"padding: 1rem"}},
+		{"before:content-none", []string{"--tw-content: none", "content: var(--tw-content)"}},
+	}
+
+	for _, tt := range tests {
+		rules := convert(t, tt.classes)
+		if len(rules) == 0 {
+			t.Errorf("%q: no rules", tt.classes)
+			continue
+		}
+		if got, want := ruleText(rules[0]), strings.Join(tt.want, "\n"); got != want {
+			t.Errorf("%q:\n%s\nwant:\n%s", tt.classes, got, want)
+		}
+	}
+}
+
+func TestSemanticNameIsIdentifier(t *testing.T) {
+	tests := []struct {
+		element string
+		want    string
+	}{
+		{"div", "div_layout_1"},
+		{"my-widget", "my_widget_layout_1"},
+		{"svg:rect", "svg_rect_layout_1"},
+		{"Foo.Bar", "foo_bar_layout_1"},
+	}
+
+	for _, tt := range tests {
+		u, _ := parser.ParseUtility("p-4")
+		group := parser.ClassGroup{
+			Element: tt.element,
+			Classes: []parser.ExtractedClass{{Name: "p-4", Utility: u, Category: "spacing"}},
+		}
+		_, mappings := NewConverter().Convert([]parser.ClassGroup{group})
+		if len(mappings) != 1 || mappings[0].SemanticName != tt.want {
+			t.Errorf("%s: got %+v, want semantic name %q", tt.element, mappings, tt.want)
+		}
+	}
+}
+
+func TestMaps(t *testing.T) {
+	tests := []struct {
+		class string
+		want  bool
+	}{
+		{"p-4", true},
+		{"hover:col-span-2", true},
+		{"[color:red]", true},
+		{"group", true},
+		{"peer/item", true},
+		{"text-primary", true}, // An unknown colour, reported rather than kept
+		{"col-md-6", false},
+		{"row-cols-2", false},
+		{"content-wrapper", false},
+		{"text-[14px]/bogus", false},
+	}
+
+	c := NewConverter()
+	for _, tt := range tests {
+		u, err := parser.ParseUtility(tt.class)
+		if err != nil {
+			t.Fatalf("ParseUtility(%q): %v", tt.class, err)
+		}
+		if got := c.Maps(u); got != tt.want {
+			t.Errorf("Maps(%s) = %v, want %v", tt.class, got, tt.want)
+		}
+	}
+}
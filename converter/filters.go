@@ -0,0 +1,126 @@
+package converter
+
+import (
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// filterValue and backdropFilterValue compose the filter functions, each set
+// through its own --tw-* variable so blur-sm and hover:brightness-110 add up
+const (
+	filterValue         = "var(--tw-blur,) var(--tw-brightness,) var(--tw-contrast,) var(--tw-grayscale,) var(--tw-hue-rotate,) var(--tw-invert,) var(--tw-saturate,) var(--tw-sepia,) var(--tw-drop-shadow,)"
+	backdropFilterValue = "var(--tw-backdrop-blur,) var(--tw-backdrop-brightness,) var(--tw-backdrop-contrast,) var(--tw-backdrop-grayscale,) var(--tw-backdrop-hue-rotate,) var(--tw-backdrop-invert,) var(--tw-backdrop-opacity,) var(--tw-backdrop-saturate,) var(--tw-backdrop-sepia,)"
+)
+
+// filterFunction describes a filter utility such as brightness-110
+type filterFunction struct {
+	name     string
+	unit     string // Unit of a numeric value, e.g. % for brightness-110
+	bare     string // Value of the utility without a value, e.g. grayscale
+	theme    string // Theme namespace of named values, e.g. blur for blur-sm
+	negative bool   // Accepts negative values, -hue-rotate-15
+	backdrop bool   // Only a backdrop filter, backdrop-opacity-50
+}
+
+var filterFunctions = []filterFunction{
+	{name: "blur", theme: "blur"},
+	{name: "brightness", unit: "%"},
+	{name: "contrast", unit: "%"},
+	{name: "grayscale", unit: "%", bare: "100%"},
+	{name: "hue-rotate", unit: "deg", negative: true},
+	{name: "invert", unit: "%", bare: "100%"},
+	{name: "opacity", unit: "%", backdrop: true},
+	{name: "saturate", unit: "%"},
+	{name: "sepia", unit: "%", bare: "100%"},
+}
+
+func (tm *TailwindMappings) initFilterMappings() {
+	tm.staticMappings["filter"] = []CSSProperty{{Name: "filter", Value: filterValue}}
+	tm.staticMappings["filter-none"] = []CSSProperty{{Name: "filter", Value: "none"}}
+	tm.staticMappings["backdrop-filter"] = backdropFilter(backdropFilterValue)
+	tm.staticMappings["backdrop-filter-none"] = backdropFilter("none")
+
+	for _, function := range filterFunctions {
+		function := function
+		if !function.backdrop {
+			variable := "--tw-" + function.name
+			tm.addDynamic(function.name, func(u *parser.ParsedUtility) []CSSProperty {
+				if value, ok := tm.filterArgument(u, function); ok {
+					return []CSSProperty{
+						{Name: variable, Value: filterCall(function.name, value)},
+						{Name: "filter", Value: filterValue},
+					}
+				}
+				return nil
+			})
+		}
+
+		variable := "--tw-backdrop-" + function.name
+		tm.addDynamic("backdrop-"+function.name, func(u *parser.ParsedUtility) []CSSProperty {
+			if value, ok := tm.filterArgument(u, function); ok {
+				return append([]CSSProperty{{Name: variable, Value: filterCall(function.name, value)}}, backdropFilter(backdropFilterValue)...)
+			}
+			return nil
+		})
+	}
+
+	// Drop shadows, one drop-shadow() per shadow of the theme value
+	tm.addDynamic("drop-shadow", func(u *parser.ParsedUtility) []CSSProperty {
+		shadows := arbitraryValue(u)
+		switch {
+		case namedValue(u) == "none":
+			return []CSSProperty{
+				{Name: "--tw-drop-shadow", Value: "initial"},
+				{Name: "filter", Value: filterValue},
+			}
+		case shadows == "":
+			var ok bool
+			if shadows, ok = tm.themeValue("drop-shadow", u); !ok {
+				return nil
+			}
+		}
+
+		var calls []string
+		for _, shadow := range splitSelectorList(shadows) {
+			calls = append(calls, "drop-shadow("+shadow+")")
+		}
+		return []CSSProperty{
+			{Name: "--tw-drop-shadow", Value: strings.Join(calls, " ")},
+			{Name: "filter", Value: filterValue},
+		}
+	})
+}
+
+// filterArgument returns the argument of a filter function utility, or
+// "initial" to clear it for blur-none
+func (tm *TailwindMappings) filterArgument(u *parser.ParsedUtility, function filterFunction) (string, bool) {
+	switch {
+	case u.Negative && !function.negative, u.Modifier != nil:
+		return "", false
+	case u.Value == nil:
+		return function.bare, function.bare != ""
+	case function.theme != "" && namedValue(u) == "none":
+		return "initial", true
+	case function.theme != "" && u.Value.Kind == parser.NamedValue:
+		return tm.themeValue(function.theme, u)
+	}
+
+	value := signedValue(u, function.unit)
+	return value, value != "" && (function.unit != "" || u.Value.Kind == parser.ArbitraryValue)
+}
+
+// filterCall writes a filter function, or keeps "initial" so the variable
+// drops out of the composed filter
+func filterCall(name, argument string) string {
+	if argument == "initial" {
+		return argument
+	}
+	return name + "(" + argument + ")"
+}
+
+func backdropFilter(value string) []CSSProperty {
+	return []CSSProperty{
+		{Name: "-webkit-backdrop-filter", Value: value},
+		{Name: "backdrop-filter", Value: value},
+	}
+}
@@ -0,0 +1,32 @@
+package converter
+
+import "testing"
+
+const wantFilter = "var(--tw-blur,) var(--tw-brightness,) var(--tw-contrast,) var(--tw-grayscale,) var(--tw-hue-rotate,) var(--tw-invert,) var(--tw-saturate,) var(--tw-sepia,) var(--tw-drop-shadow,)"
+
+func TestFilterComposition(t *testing.T) {
+	filters := []string{"--tw-blur", "--tw-brightness", "--tw-contrast", "--tw-grayscale", "--tw-hue-rotate", "--tw-invert", "--tw-saturate", "--tw-sepia", "--tw-drop-shadow"}
+	tests := []struct {
+		classes    string
+		want       []string
+		properties []string
+	}{
+		{
+			classes:    "blur-sm brightness-110",
+			want:       []string{"{ .div_1 } --tw-blur: blur(8px); --tw-brightness: brightness(110%); filter: " + wantFilter},
+			properties: filters,
+		},
+		{
+			classes: "blur-sm hover:brightness-110",
+			want: []string{
+				"{ .div_1 } --tw-blur: blur(8px); filter: " + wantFilter,
+				"@media (hover: hover) { .div_1:hover } --tw-brightness: brightness(110%); filter: " + wantFilter,
+			},
+			properties: filters,
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, tt.want, tt.properties)
+	}
+}
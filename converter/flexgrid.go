@@ -0,0 +1,131 @@
+package converter
+
+import (
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+func (tm *TailwindMappings) initFlexGridMappings() {
+	// Flex items, flex-1, flex-auto and flex-1/2
+	tm.staticMappings["flex-auto"] = []CSSProperty{{Name: "flex", Value: "1 1 auto"}}
+	tm.staticMappings["flex-initial"] = []CSSProperty{{Name: "flex", Value: "0 1 auto"}}
+	tm.staticMappings["flex-none"] = []CSSProperty{{Name: "flex", Value: "none"}}
+	tm.addDynamic("flex", func(u *parser.ParsedUtility) []CSSProperty {
+		flex := numericValue(u)
+		if u.Value != nil && u.Value.Fraction != "" && !u.Negative {
+			flex = fractionPercent(u.Value.Fraction)
+		}
+		if flex == "" {
+			return nil
+		}
+		return []CSSProperty{{Name: "flex", Value: flex}}
+	})
+	tm.addKeywords("flex", "flex-wrap", "wrap", "wrap-reverse", "nowrap")
+
+	// Grow and shrink, grow, grow-0 and shrink-0
+	for root, property := range map[string]string{"grow": "flex-grow", "shrink": "flex-shrink"} {
+		property := property
+		tm.staticMappings[root] = []CSSProperty{{Name: property, Value: "1"}}
+		tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+			if factor := numericValue(u); factor != "" {
+				return []CSSProperty{{Name: property, Value: factor}}
+			}
+			return nil
+		})
+	}
+
+	// Order, order-2, -order-1 and order-first
+	tm.staticMappings["order-first"] = []CSSProperty{{Name: "order", Value: "calc(-infinity)"}}
+	tm.staticMappings["order-last"] = []CSSProperty{{Name: "order", Value: "calc(infinity)"}}
+	tm.staticMappings["order-none"] = []CSSProperty{{Name: "order", Value: "0"}}
+	tm.addDynamic("order", func(u *parser.ParsedUtility) []CSSProperty {
+		if order := gridInteger(u); order != "" {
+			return []CSSProperty{{Name: "order", Value: order}}
+		}
+		return nil
+	})
+
+	// Grid templates besides grid-cols-3, grid-cols-subgrid for nested grids
+	for _, axis := range []string{"cols", "rows"} {
+		property := map[string]string{"cols": "grid-template-columns", "rows": "grid-template-rows"}[axis]
+		tm.staticMappings["grid-"+axis+"-none"] = []CSSProperty{{Name: property, Value: "none"}}
+		tm.staticMappings["grid-"+axis+"-subgrid"] = []CSSProperty{{Name: property, Value: "subgrid"}}
+	}
+
+	// Grid placement, col-span-2, col-start-3, row-span-full and row-3
+	for _, axis := range []string{"col", "row"} {
+		property := map[string]string{"col": "grid-column", "row": "grid-row"}[axis]
+		tm.staticMappings[axis+"-auto"] = []CSSProperty{{Name: property, Value: "auto"}}
+		tm.staticMappings[axis+"-span-full"] = []CSSProperty{{Name: property, Value: "1 / -1"}}
+		tm.staticMappings[axis+"-start-auto"] = []CSSProperty{{Name: property + "-start", Value: "auto"}}
+		tm.staticMappings[axis+"-end-auto"] = []CSSProperty{{Name: property + "-end", Value: "auto"}}
+		tm.addDynamic(axis, func(u *parser.ParsedUtility) []CSSProperty {
+			if line := gridInteger(u); line != "" {
+				return []CSSProperty{{Name: property, Value: line}}
+			}
+			return nil
+		})
+		tm.addDynamic(axis+"-span", func(u *parser.ParsedUtility) []CSSProperty {
+			span := numericValue(u)
+			if span == "" {
+				span = arbitraryValue(u)
+			}
+			if span == "" {
+				return nil
+			}
+			return []CSSProperty{{Name: property, Value: "span " + span + " / span " + span}}
+		})
+		for _, edge := range []string{"start", "end"} {
+			edgeProperty := property + "-" + edge
+			tm.addDynamic(axis+"-"+edge, func(u *parser.ParsedUtility) []CSSProperty {
+				if line := gridInteger(u); line != "" {
+					return []CSSProperty{{Name: edgeProperty, Value: line}}
+				}
+				return nil
+			})
+		}
+	}
+
+	// Auto placement and implicit tracks, grid-flow-col-dense and auto-cols-fr
+	for keyword, flow := range map[string]string{
+		"row": "row", "col": "column", "dense": "dense",
+		"row-dense": "row dense", "col-dense": "column dense",
+	} {
+		tm.staticMappings["grid-flow-"+keyword] = []CSSProperty{{Name: "grid-auto-flow", Value: flow}}
+	}
+	for keyword, size := range map[string]string{
+		"auto": "auto", "min": "min-content", "max": "max-content", "fr": "minmax(0, 1fr)",
+	} {
+		tm.staticMappings["auto-cols-"+keyword] = []CSSProperty{{Name: "grid-auto-columns", Value: size}}
+		tm.staticMappings["auto-rows-"+keyword] = []CSSProperty{{Name: "grid-auto-rows", Value: size}}
+	}
+
+	// Item alignment, self-end, justify-self-center and place-self-start
+	for keyword, align := range map[string]string{
+		"auto": "auto", "start": "flex-start", "end": "flex-end",
+		"center": "center", "stretch": "stretch", "baseline": "baseline",
+	} {
+		tm.staticMappings["self-"+keyword] = []CSSProperty{{Name: "align-self", Value: align}}
+	}
+	tm.addKeywords("justify-self", "justify-self", "auto", "start", "end", "center", "stretch")
+	tm.addKeywords("justify-items", "justify-items", "start", "end", "center", "stretch")
+	tm.addKeywords("place-self", "place-self", "auto", "start", "end", "center", "stretch")
+	tm.addKeywords("place-items", "place-items", "start", "end", "center", "baseline", "stretch")
+	tm.addKeywords("justify", "justify-content", "stretch", "normal")
+}
+
+// gridInteger returns the integer of a utility like order-2 or
+// -col-start-1, or "" for anything else
+func gridInteger(u *parser.ParsedUtility) string {
+	integer := ""
+	if u.Value != nil && u.Value.Kind == parser.NamedValue && u.Modifier == nil {
+		integer = u.Value.Text
+	}
+	if !numberPattern.MatchString(integer) || strings.Contains(integer, ".") {
+		return ""
+	}
+	if u.Negative && integer != "0" {
+		return "-" + integer
+	}
+	return integer
+}
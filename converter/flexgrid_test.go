@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlexGridItemUtilities(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string // Declarations joined by "; ", empty when the class does not convert
+	}{
+		{"flex-1", "flex: 1"},
+		{"flex-[2_2_0%]", "flex: 2 2 0%"},
+		{"flex-1/2", "flex: 50%"},
+		{"grow", "flex-grow: 1"},
+		{"grow-0", "flex-grow: 0"},
+		{"shrink-[2]", "flex-shrink: 2"},
+		{"order-first", "order: calc(-infinity)"},
+		{"order-3", "order: 3"},
+		{"-order-1", "order: -1"},
+		{"col-span-2", "grid-column: span 2 / span 2"},
+		{"col-span-[7]", "grid-column: span 7 / span 7"},
+		{"-col-span-2", ""},
+		{"col-start-2", "grid-column-start: 2"},
+		{"col-[span_3/span_3]", "grid-column: span 3/span 3"},
+		{"row-span-full", "grid-row: 1 / -1"},
+		{"row-end-auto", "grid-row-end: auto"},
+		{"grid-cols-3", "grid-template-columns: repeat(3, minmax(0, 1fr))"},
+		{"grid-cols-[200px_1fr]", "grid-template-columns: 200px 1fr"},
+		{"grid-rows-subgrid", "grid-template-rows: subgrid"},
+		{"grid-flow-row-dense", "grid-auto-flow: row dense"},
+		{"auto-cols-fr", "grid-auto-columns: minmax(0, 1fr)"},
+		{"self-center", "align-self: center"},
+		{"justify-self-end", "justify-self: end"},
+		{"place-self-stretch", "place-self: stretch"},
+	}
+
+	tm := NewTailwindMappings(DefaultOptions())
+	for _, tt := range tests {
+		if got := strings.Join(declarations(t, tm, tt.class), "; "); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.class, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package converter
+
+import "tailwind-v4-to-css-converter/internal/parser"
+
+// gradientStops lists the colour stops of a gradient: from, then via when
+// a via-* class has set --tw-gradient-via-stops, then to. The position and
+// interpolation of bg-linear-to-r/oklch lead the list.
+const gradientStops = "var(--tw-gradient-via-stops, var(--tw-gradient-position), var(--tw-gradient-from) var(--tw-gradient-from-position), var(--tw-gradient-to) var(--tw-gradient-to-position))"
+
+const gradientViaStops = "var(--tw-gradient-position), var(--tw-gradient-from) var(--tw-gradient-from-position), var(--tw-gradient-via) var(--tw-gradient-via-position), var(--tw-gradient-to) var(--tw-gradient-to-position)"
+
+// Roots of the gradient colour stops, from-indigo-500 and the like
+var gradientStopRoots = setOf("from", "via", "to")
+
+// Directions of bg-linear-to-*
+var gradientDirections = map[string]string{
+	"to-t": "to top", "to-tr": "to top right", "to-r": "to right",
+	"to-br": "to bottom right", "to-b": "to bottom", "to-bl": "to bottom left",
+	"to-l": "to left", "to-tl": "to top left",
+}
+
+// Polar colour spaces, whose hue interpolation can be set with a modifier
+// like bg-conic/longer
+var hueInterpolations = setOf("shorter", "longer", "increasing", "decreasing")
+
+func (tm *TailwindMappings) initGradientMappings() {
+	// Linear gradients, bg-linear-to-r and bg-linear-45; bg-gradient-to-r
+	// is the v3 spelling
+	linear := func(u *parser.ParsedUtility) []CSSProperty {
+		if direction, ok := gradientDirections[namedValueWithModifier(u)]; ok && !u.Negative {
+			return gradient("linear-gradient", direction, u)
+		}
+		if u.Root != "bg-linear" || u.Value == nil {
+			return nil
+		}
+		if u.Value.Kind == parser.ArbitraryValue {
+			return arbitraryGradient("linear-gradient", u)
+		}
+		unmodified := *u
+		unmodified.Modifier = nil
+		if angle := angleValue(&unmodified); angle != "" {
+			return gradient("linear-gradient", angle, u)
+		}
+		return nil
+	}
+	tm.addDynamic("bg-linear", linear)
+	tm.addDynamic("bg-gradient", linear)
+
+	// Radial gradients, bg-radial and bg-radial-[at_25%_25%]
+	tm.addDynamic("bg-radial", func(u *parser.ParsedUtility) []CSSProperty {
+		switch {
+		case u.Negative:
+			return nil
+		case u.Value == nil:
+			return gradient("radial-gradient", "", u)
+		case u.Value.Kind == parser.ArbitraryValue:
+			return arbitraryGradient("radial-gradient", u)
+		}
+		return nil
+	})
+
+	// Conic gradients, bg-conic and bg-conic-180
+	tm.addDynamic("bg-conic", func(u *parser.ParsedUtility) []CSSProperty {
+		if u.Value == nil {
+			if u.Negative {
+				return nil
+			}
+			return gradient("conic-gradient", "", u)
+		}
+		if u.Value.Kind == parser.ArbitraryValue {
+			return arbitraryGradient("conic-gradient", u)
+		}
+		unmodified := *u
+		unmodified.Modifier = nil
+		if angle := angleValue(&unmodified); angle != "" {
+			return gradient("conic-gradient", "from "+angle, u)
+		}
+		return nil
+	})
+
+	// Colour stops and their positions, from-indigo-500 and from-10%
+	tm.addDynamic("from", func(u *parser.ParsedUtility) []CSSProperty {
+		return tm.gradientStop(u, "--tw-gradient-from",
+			CSSProperty{Name: "--tw-gradient-stops", Value: gradientStops})
+	})
+	tm.addDynamic("via", func(u *parser.ParsedUtility) []CSSProperty {
+		return tm.gradientStop(u, "--tw-gradient-via",
+			CSSProperty{Name: "--tw-gradient-via-stops", Value: gradientViaStops},
+			CSSProperty{Name: "--tw-gradient-stops", Value: "var(--tw-gradient-via-stops)"})
+	})
+	tm.addDynamic("to", func(u *parser.ParsedUtility) []CSSProperty {
+		return tm.gradientStop(u, "--tw-gradient-to",
+			CSSProperty{Name: "--tw-gradient-stops", Value: gradientStops})
+	})
+}
+
+// namedValueWithModifier returns the named value of a utility whose
+// modifier is not part of the value, like the to-r of bg-linear-to-r/oklch
+func namedValueWithModifier(u *parser.ParsedUtility) string {
+	if u.Value == nil || u.Value.Kind != parser.NamedValue {
+		return ""
+	}
+	return u.Value.Text
+}
+
+// gradient sets the position of a gradient, with the interpolation of its
+// modifier, and the background drawing its stops
+func gradient(function, position string, u *parser.ParsedUtility) []CSSProperty {
+	interpolation := gradientInterpolation(u.Modifier)
+	if interpolation == "" {
+		return nil
+	}
+	if position != "" {
+		position += " "
+	}
+	return []CSSProperty{
+		{Name: "--tw-gradient-position", Value: position + interpolation},
+		{Name: "background-image", Value: function + "(var(--tw-gradient-stops))"},
+	}
+}
+
+// arbitraryGradient draws a gradient from a bracketed value, which may be a
+// position like at_25%_25% for the stop classes or a complete gradient
+func arbitraryGradient(function string, u *parser.ParsedUtility) []CSSProperty {
+	value := arbitraryValue(u)
+	if value == "" {
+		return nil
+	}
+	return []CSSProperty{
+		{Name: "--tw-gradient-position", Value: value},
+		{Name: "background-image", Value: function + "(var(--tw-gradient-stops, " + value + "))"},
+	}
+}
+
+// gradientInterpolation returns the colour interpolation of a gradient
+// modifier: in oklab by default, in oklch for /oklch, in oklch longer hue
+// for /longer, or a bracketed value as written
+func gradientInterpolation(modifier *parser.UtilityValue) string {
+	switch {
+	case modifier == nil:
+		return "in oklab"
+	case modifier.Kind == parser.ArbitraryValue:
+		return modifier.Text
+	case hueInterpolations[modifier.Text]:
+		return "in oklch " + modifier.Text + " hue"
+	case modifier.Text == "srgb" || modifier.Text == "hsl" || modifier.Text == "oklab" || modifier.Text == "oklch":
+		return "in " + modifier.Text
+	}
+	return ""
+}
+
+// gradientStop sets a gradient colour stop and the stop lists it adds to,
+// or the position of the stop for a percentage or length
+func (tm *TailwindMappings) gradientStop(u *parser.ParsedUtility, variable string, stops ...CSSProperty) []CSSProperty {
+	if u.Value == nil || u.Negative {
+		return nil
+	}
+
+	position := ""
+	switch u.Value.Kind {
+	case parser.NamedValue:
+		if u.Modifier == nil && percentagePattern.MatchString(u.Value.Text) {
+			position = u.Value.Text
+		}
+	case parser.ArbitraryValue:
+		dataType := u.Value.DataType
+		if dataType == "" {
+			dataType = inferDataType(u.Value.Text)
+		}
+		if dataType == "percentage" || dataType == "length" || dataType == "length-percentage" {
+			position = arbitraryValue(u)
+		}
+	}
+	if position != "" {
+		return []CSSProperty{{Name: variable + "-position", Value: position}}
+	}
+
+	colors, ok := tm.utilityColor(u)
+	if !ok {
+		return nil
+	}
+	var props []CSSProperty
+	for _, color := range colors {
+		props = append(props, CSSProperty{Name: variable, Value: color})
+	}
+	return append(props, stops...)
+}
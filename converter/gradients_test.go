@@ -0,0 +1,54 @@
+package converter
+
+import "testing"
+
+const wantGradientStops = "--tw-gradient-stops: var(--tw-gradient-via-stops, var(--tw-gradient-position), var(--tw-gradient-from) var(--tw-gradient-from-position), var(--tw-gradient-to) var(--tw-gradient-to-position))"
+
+func TestGradients(t *testing.T) {
+	tests := []struct {
+		classes    string
+		want       string
+		properties []string
+	}{
+		{
+			classes: "bg-linear-to-r from-blue-500 to-red-500",
+			want: "{ .div_1 } --tw-gradient-position: to right in oklab; background-image: linear-gradient(var(--tw-gradient-stops)); " +
+				"--tw-gradient-from: oklch(62.3% 0.214 259.815); --tw-gradient-to: oklch(63.7% 0.237 25.331); " + wantGradientStops,
+			properties: []string{"--tw-gradient-position", "--tw-gradient-stops", "--tw-gradient-from", "--tw-gradient-to", "--tw-gradient-via-stops", "--tw-gradient-from-position", "--tw-gradient-to-position"},
+		},
+		{
+			classes: "bg-linear-to-r from-blue-500 from-10% via-green-500 via-30% to-red-500 to-90%",
+			want: "{ .div_1 } --tw-gradient-position: to right in oklab; background-image: linear-gradient(var(--tw-gradient-stops)); " +
+				"--tw-gradient-via: oklch(72.3% 0.219 149.579); --tw-gradient-via-stops: var(--tw-gradient-position), var(--tw-gradient-from) var(--tw-gradient-from-position), var(--tw-gradient-via) var(--tw-gradient-via-position), var(--tw-gradient-to) var(--tw-gradient-to-position); " +
+				"--tw-gradient-from: oklch(62.3% 0.214 259.815); --tw-gradient-to: oklch(63.7% 0.237 25.331); " + wantGradientStops + "; " +
+				"--tw-gradient-from-position: 10%; --tw-gradient-via-position: 30%; --tw-gradient-to-position: 90%",
+		},
+		{
+			classes: "bg-linear-to-r/oklch from-blue-500/50",
+			want: "{ .div_1 } --tw-gradient-position: to right in oklch; background-image: linear-gradient(var(--tw-gradient-stops)); " +
+				"--tw-gradient-from: color-mix(in oklab, oklch(62.3% 0.214 259.815) 50%, transparent); " + wantGradientStops,
+		},
+		{
+			classes:    "bg-linear-45/srgb",
+			want:       "{ .div_1 } --tw-gradient-position: 45deg in srgb; background-image: linear-gradient(var(--tw-gradient-stops))",
+			properties: []string{"--tw-gradient-position", "--tw-gradient-stops"},
+		},
+		{
+			classes: "bg-radial from-white to-black",
+			want: "{ .div_1 } --tw-gradient-position: in oklab; background-image: radial-gradient(var(--tw-gradient-stops)); " +
+				"--tw-gradient-from: #fff; --tw-gradient-to: #000; " + wantGradientStops,
+		},
+		{
+			classes: "bg-conic/decreasing",
+			want:    "{ .div_1 } --tw-gradient-position: in oklch decreasing hue; background-image: conic-gradient(var(--tw-gradient-stops))",
+		},
+		{
+			classes: "bg-linear-[to_right,red,blue]",
+			want:    "{ .div_1 } --tw-gradient-position: to right,red,blue; background-image: linear-gradient(var(--tw-gradient-stops, to right,red,blue))",
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, []string{tt.want}, tt.properties)
+	}
+}
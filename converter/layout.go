@@ -0,0 +1,116 @@
+package converter
+
+import "tailwind-v4-to-css-converter/internal/parser"
+
+// Positions of object-*, in both the v4.0 (left-top) and v4.1 (top-left)
+// spellings
+var objectPositions = map[string]string{
+	"center": "center", "top": "top", "right": "right", "bottom": "bottom",
+	"left": "left", "top-left": "left top", "left-top": "left top",
+	"top-right": "right top", "right-top": "right top",
+	"bottom-left": "left bottom", "left-bottom": "left bottom",
+	"bottom-right": "right bottom", "right-bottom": "right bottom",
+}
+
+func (tm *TailwindMappings) initLayoutMappings() {
+	// Display, the values besides flex, grid, block and the like above
+	for _, display := range []string{
+		"inline-grid", "flow-root", "contents", "list-item", "table",
+		"inline-table", "table-caption", "table-cell", "table-column",
+		"table-column-group", "table-footer-group", "table-header-group",
+		"table-row-group", "table-row",
+	} {
+		tm.staticMappings[display] = []CSSProperty{{Name: "display", Value: display}}
+	}
+
+	// Visibility
+	tm.staticMappings["visible"] = []CSSProperty{{Name: "visibility", Value: "visible"}}
+	tm.staticMappings["invisible"] = []CSSProperty{{Name: "visibility", Value: "hidden"}}
+	tm.staticMappings["collapse"] = []CSSProperty{{Name: "visibility", Value: "collapse"}}
+
+	// Box sizing, box decoration and isolation
+	tm.staticMappings["box-border"] = []CSSProperty{{Name: "box-sizing", Value: "border-box"}}
+	tm.staticMappings["box-content"] = []CSSProperty{{Name: "box-sizing", Value: "content-box"}}
+	tm.addKeywords("box-decoration", "box-decoration-break", "clone", "slice")
+	tm.staticMappings["isolate"] = []CSSProperty{{Name: "isolation", Value: "isolate"}}
+	tm.staticMappings["isolation-auto"] = []CSSProperty{{Name: "isolation", Value: "auto"}}
+
+	// Floats, float-right and float-start
+	tm.addKeywords("float", "float", "left", "right", "none")
+	tm.addKeywords("clear", "clear", "left", "right", "both", "none")
+	for _, side := range []string{"start", "end"} {
+		tm.staticMappings["float-"+side] = []CSSProperty{{Name: "float", Value: "inline-" + side}}
+		tm.staticMappings["clear-"+side] = []CSSProperty{{Name: "clear", Value: "inline-" + side}}
+	}
+
+	// Overflow and overscroll
+	for _, root := range []string{"overflow", "overflow-x", "overflow-y"} {
+		tm.addKeywords(root, root, "auto", "hidden", "clip", "visible", "scroll")
+	}
+	for _, axis := range []string{"", "-x", "-y"} {
+		tm.addKeywords("overscroll"+axis, "overscroll-behavior"+axis, "auto", "contain", "none")
+	}
+
+	// Column and page breaks
+	tm.addKeywords("break-before", "break-before", "auto", "avoid", "all", "avoid-page", "page", "left", "right", "column")
+	tm.addKeywords("break-after", "break-after", "auto", "avoid", "all", "avoid-page", "page", "left", "right", "column")
+	tm.addKeywords("break-inside", "break-inside", "auto", "avoid", "avoid-page", "avoid-column")
+
+	// Object fit and position, object-cover and object-top-left
+	tm.addKeywords("object", "object-fit", "contain", "cover", "fill", "none", "scale-down")
+	for keyword, position := range objectPositions {
+		tm.staticMappings["object-"+keyword] = []CSSProperty{{Name: "object-position", Value: position}}
+	}
+
+	// Z-index, z-50 and -z-10
+	tm.staticMappings["z-auto"] = []CSSProperty{{Name: "z-index", Value: "auto"}}
+	tm.addDynamic("z", func(u *parser.ParsedUtility) []CSSProperty {
+		if u.Value == nil || u.Modifier != nil {
+			return nil
+		}
+		z := u.Value.Text
+		if u.Value.Kind == parser.NamedValue && !numberPattern.MatchString(z) {
+			return nil
+		}
+		if u.Negative {
+			z = negateLength(z)
+		}
+		return []CSSProperty{{Name: "z-index", Value: z}}
+	})
+
+	// Aspect ratio, aspect-video from the theme and aspect-4/3
+	tm.staticMappings["aspect-auto"] = []CSSProperty{{Name: "aspect-ratio", Value: "auto"}}
+	tm.staticMappings["aspect-square"] = []CSSProperty{{Name: "aspect-ratio", Value: "1 / 1"}}
+	tm.addDynamic("aspect", func(u *parser.ParsedUtility) []CSSProperty {
+		ratio, _ := tm.themeValue("aspect", u)
+		if u.Value != nil && u.Value.Fraction != "" && !u.Negative && u.Modifier != nil &&
+			numberPattern.MatchString(u.Value.Text) && numberPattern.MatchString(u.Modifier.Text) {
+			ratio = u.Value.Text + " / " + u.Modifier.Text
+		}
+		if ratio == "" {
+			return nil
+		}
+		return []CSSProperty{{Name: "aspect-ratio", Value: ratio}}
+	})
+
+	// Columns, columns-3 or columns-xs from the theme's container sizes
+	tm.staticMappings["columns-auto"] = []CSSProperty{{Name: "columns", Value: "auto"}}
+	tm.addDynamic("columns", func(u *parser.ParsedUtility) []CSSProperty {
+		columns := numericValue(u)
+		if columns == "" {
+			columns, _ = tm.themeValue("container", u)
+		}
+		if columns == "" {
+			return nil
+		}
+		return []CSSProperty{{Name: "columns", Value: columns}}
+	})
+}
+
+// addKeywords registers root-<keyword> utilities setting a property to the
+// keyword itself, like float-left
+func (tm *TailwindMappings) addKeywords(root, property string, keywords ...string) {
+	for _, keyword := range keywords {
+		tm.staticMappings[root+"-"+keyword] = []CSSProperty{{Name: property, Value: keyword}}
+	}
+}
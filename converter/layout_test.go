@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutUtilities(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string // Declarations joined by "; ", empty when the class does not convert
+	}{
+		{"z-10", "z-index: 10"},
+		{"-z-10", "z-index: -10"},
+		{"z-[999]", "z-index: 999"},
+		{"z-auto", "z-index: auto"},
+		{"overflow-x-auto", "overflow-x: auto"},
+		{"overscroll-y-contain", "overscroll-behavior-y: contain"},
+		{"aspect-video", "aspect-ratio: 16 / 9"},
+		{"aspect-3/2", "aspect-ratio: 3 / 2"},
+		{"aspect-square", "aspect-ratio: 1 / 1"},
+		{"aspect-[4/3]", "aspect-ratio: 4/3"},
+		{"columns-3", "columns: 3"},
+		{"columns-xs", "columns: 20rem"},
+		{"float-start", "float: inline-start"},
+		{"clear-both", "clear: both"},
+		{"object-cover", "object-fit: cover"},
+		{"object-left-top", "object-position: left top"},
+		{"break-inside-avoid", "break-inside: avoid"},
+		{"isolate", "isolation: isolate"},
+		{"box-decoration-clone", "box-decoration-break: clone"},
+		{"invisible", "visibility: hidden"},
+	}
+
+	tm := NewTailwindMappings(DefaultOptions())
+	for _, tt := range tests {
+		if got := strings.Join(declarations(t, tm, tt.class), "; "); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.class, got, tt.want)
+		}
+	}
+}
@@ -3,46 +3,141 @@ package converter
 import (
 	"regexp"
 	"strconv"
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
 )
 
 type TailwindMappings struct {
-	staticMappings map[string][]CSSProperty
-	dynamicRegex   []*DynamicMapping
+	staticMappings  map[string][]CSSProperty
+	dynamicMappings []*DynamicMapping
+	selectors       map[string]string                           // Selector templates of roots styling other elements, by root
+	breakpointSteps map[string]func(width string) []CSSProperty // Declarations of utilities like container at each breakpoint
+	theme           *parser.Theme
+	colorFormat     ColorFormat
+	colorFallback   bool
 }
 
+// DynamicMapping converts the utilities sharing a root, e.g. every p-*
+// class. Convert returns no properties when it does not handle the value.
 type DynamicMapping struct {
-	Pattern *regexp.Regexp
-	Convert func(matches []string) []CSSProperty
+	Root    string
+	Convert func(u *parser.ParsedUtility) []CSSProperty
 }
 
-func NewTailwindMappings() *TailwindMappings {
+var (
+	numberPattern    = regexp.MustCompile(`^\d+(?:\.\d+)?$`)
+	dimensionPattern = regexp.MustCompile(`^(-?\d*\.?\d+)([a-z%]*)$`)
+)
+
+func NewTailwindMappings(options Options) *TailwindMappings {
 	tm := &TailwindMappings{
-		staticMappings: make(map[string][]CSSProperty),
-		dynamicRegex:   []*DynamicMapping{},
+		staticMappings:  make(map[string][]CSSProperty),
+		dynamicMappings: []*DynamicMapping{},
+		selectors:       make(map[string]string),
+		breakpointSteps: make(map[string]func(width string) []CSSProperty),
+		theme:           options.Theme,
+		colorFormat:     options.ColorFormat,
+		colorFallback:   options.ColorFallback,
 	}
 
 	tm.initStaticMappings()
 	tm.initDynamicMappings()
+	tm.initSpacingMappings()
+	tm.initChildMappings()
+	tm.initTransformMappings()
+	tm.initShadowMappings()
+	tm.initGradientMappings()
+	tm.initAnimationMappings()
+	tm.initCompositeMappings()
+	tm.initLayoutMappings()
+	tm.initFlexGridMappings()
+	tm.initTypographyMappings()
+	tm.initFilterMappings()
+	tm.initArbitraryMappings()
 
 	return tm
 }
 
-func (tm *TailwindMappings) Convert(class string) []CSSProperty {
+// Convert returns the declarations of a utility, ignoring its variants
+func (tm *TailwindMappings) Convert(u *parser.ParsedUtility) []CSSProperty {
 	// Try static mappings first
-	if props, exists := tm.staticMappings[class]; exists {
+	if props, exists := tm.staticMappings[u.Base()]; exists {
 		return props
 	}
 
-	// Try dynamic mappings
-	for _, mapping := range tm.dynamicRegex {
-		if matches := mapping.Pattern.FindStringSubmatch(class); matches != nil {
-			return mapping.Convert(matches)
+	// Try dynamic mappings registered for the utility's root
+	for _, mapping := range tm.dynamicMappings {
+		if mapping.Root != u.Root {
+			continue
+		}
+		if props := mapping.Convert(u); len(props) > 0 {
+			return props
 		}
 	}
 
 	return []CSSProperty{}
 }
 
+// BreakpointStep is what a utility like container declares from one
+// breakpoint up
+type BreakpointStep struct {
+	Breakpoint string // Variant name, e.g. "md"
+	Properties []CSSProperty
+}
+
+// BreakpointSteps returns the declarations a utility adds at each of the
+// theme's breakpoints, narrowest first
+func (tm *TailwindMappings) BreakpointSteps(u *parser.ParsedUtility) []BreakpointStep {
+	step, exists := tm.breakpointSteps[u.Base()]
+	if !exists {
+		return nil
+	}
+	var steps []BreakpointStep
+	for _, breakpoint := range themeWidths(tm.theme, "breakpoint") {
+		steps = append(steps, BreakpointStep{Breakpoint: breakpoint.name, Properties: step(breakpoint.width)})
+	}
+	return steps
+}
+
+// Selector returns the selector template a utility's declarations go on,
+// "&" for the element itself or e.g. childSelector for space-x-4
+func (tm *TailwindMappings) Selector(u *parser.ParsedUtility) string {
+	if selector, exists := tm.selectors[u.Root]; exists && u.Property == "" {
+		return selector
+	}
+	return "&"
+}
+
+func (tm *TailwindMappings) addDynamic(root string, convert func(u *parser.ParsedUtility) []CSSProperty) {
+	tm.dynamicMappings = append(tm.dynamicMappings, &DynamicMapping{Root: root, Convert: convert})
+}
+
+// namedValue returns the plain named value of a non-negative utility, or ""
+func namedValue(u *parser.ParsedUtility) string {
+	if u.Value == nil || u.Value.Kind != parser.NamedValue || u.Negative || u.Modifier != nil {
+		return ""
+	}
+	return u.Value.Text
+}
+
+// numericValue returns the named value if it is a number, like the 4 in p-4
+func numericValue(u *parser.ParsedUtility) string {
+	if value := namedValue(u); numberPattern.MatchString(value) {
+		return value
+	}
+	return ""
+}
+
+// themeValue looks up the theme token of a namespace for a named value,
+// e.g. --radius-lg for rounded-lg
+func (tm *TailwindMappings) themeValue(namespace string, u *parser.ParsedUtility) (string, bool) {
+	value := namedValue(u)
+	if value == "" {
+		return "", false
+	}
+	return tm.theme.Value("--" + namespace + "-" + value)
+}
+
 func (tm *TailwindMappings) initStaticMappings() {
 	// Display
 	tm.staticMappings["flex"] = []CSSProperty{{Name: "display", Value: "flex"}}
@@ -78,16 +173,8 @@ func (tm *TailwindMappings) initStaticMappings() {
 	tm.staticMappings["text-center"] = []CSSProperty{{Name: "text-align", Value: "center"}}
 	tm.staticMappings["text-right"] = []CSSProperty{{Name: "text-align", Value: "right"}}
 	tm.staticMappings["text-justify"] = []CSSProperty{{Name: "text-align", Value: "justify"}}
-
-	// Font Weight
-	tm.staticMappings["font-thin"] = []CSSProperty{{Name: "font-weight", Value: "100"}}
-	tm.staticMappings["font-light"] = []CSSProperty{{Name: "font-weight", Value: "300"}}
-	tm.staticMappings["font-normal"] = []CSSProperty{{Name: "font-weight", Value: "400"}}
-	tm.staticMappings["font-medium"] = []CSSProperty{{Name: "font-weight", Value: "500"}}
-	tm.staticMappings["font-semibold"] = []CSSProperty{{Name: "font-weight", Value: "600"}}
-	tm.staticMappings["font-bold"] = []CSSProperty{{Name: "font-weight", Value: "700"}}
-	tm.staticMappings["font-extrabold"] = []CSSProperty{{Name: "font-weight", Value: "800"}}
-	tm.staticMappings["font-black"] = []CSSProperty{{Name: "font-weight", Value: "900"}}
+	tm.staticMappings["text-start"] = []CSSProperty{{Name: "text-align", Value: "start"}}
+	tm.staticMappings["text-end"] = []CSSProperty{{Name: "text-align", Value: "end"}}
 
 	// Position
 	tm.staticMappings["static"] = []CSSProperty{{Name: "position", Value: "static"}}
@@ -97,15 +184,8 @@ func (tm *TailwindMappings) initStaticMappings() {
 	tm.staticMappings["sticky"] = []CSSProperty{{Name: "position", Value: "sticky"}}
 
 	// Common Layout Classes
-	tm.staticMappings["container"] = []CSSProperty{{Name: "max-width", Value: "1200px"}, {Name: "margin", Value: "0 auto"}}
 	tm.staticMappings["mx-auto"] = []CSSProperty{{Name: "margin-left", Value: "auto"}, {Name: "margin-right", Value: "auto"}}
 
-	// Common Visual Classes
-	tm.staticMappings["bg-white"] = []CSSProperty{{Name: "background-color", Value: "#ffffff"}}
-	tm.staticMappings["bg-black"] = []CSSProperty{{Name: "background-color", Value: "#000000"}}
-	tm.staticMappings["text-white"] = []CSSProperty{{Name: "color", Value: "#ffffff"}}
-	tm.staticMappings["text-black"] = []CSSProperty{{Name: "color", Value: "#000000"}}
-
 	// Border
 	tm.staticMappings["border"] = []CSSProperty{{Name: "border", Value: "1px solid #e5e7eb"}}
 	tm.staticMappings["border-b"] = []CSSProperty{{Name: "border-bottom", Value: "1px solid #e5e7eb"}}
@@ -115,192 +195,214 @@ func (tm *TailwindMappings) initStaticMappings() {
 
 	// Rounded
 	tm.staticMappings["rounded"] = []CSSProperty{{Name: "border-radius", Value: "0.25rem"}}
-	tm.staticMappings["rounded-md"] = []CSSProperty{{Name: "border-radius", Value: "0.375rem"}}
-	tm.staticMappings["rounded-lg"] = []CSSProperty{{Name: "border-radius", Value: "0.5rem"}}
-	tm.staticMappings["rounded-xl"] = []CSSProperty{{Name: "border-radius", Value: "0.75rem"}}
-	tm.staticMappings["rounded-2xl"] = []CSSProperty{{Name: "border-radius", Value: "1rem"}}
+	tm.staticMappings["rounded-none"] = []CSSProperty{{Name: "border-radius", Value: "0"}}
 	tm.staticMappings["rounded-full"] = []CSSProperty{{Name: "border-radius", Value: "9999px"}}
 
-	// Shadow
-	tm.staticMappings["shadow"] = []CSSProperty{{Name: "box-shadow", Value: "0 1px 3px 0 rgba(0, 0, 0, 0.1), 0 1px 2px 0 rgba(0, 0, 0, 0.06)"}}
-	tm.staticMappings["shadow-md"] = []CSSProperty{{Name: "box-shadow", Value: "0 4px 6px -1px rgba(0, 0, 0, 0.1), 0 2px 4px -1px rgba(0, 0, 0, 0.06)"}}
-	tm.staticMappings["shadow-lg"] = []CSSProperty{{Name: "box-shadow", Value: "0 10px 15px -3px rgba(0, 0, 0, 0.1), 0 4px 6px -2px rgba(0, 0, 0, 0.05)"}}
-
-	// Transition
-	tm.staticMappings["transition-shadow"] = []CSSProperty{{Name: "transition", Value: "box-shadow 150ms ease-in-out"}}
-	tm.staticMappings["transition-colors"] = []CSSProperty{{Name: "transition", Value: "color, background-color, border-color 150ms ease-in-out"}}
-
 	// Grid
 	tm.staticMappings["grid-cols-1"] = []CSSProperty{{Name: "grid-template-columns", Value: "repeat(1, minmax(0, 1fr))"}}
 	tm.staticMappings["grid-cols-2"] = []CSSProperty{{Name: "grid-template-columns", Value: "repeat(2, minmax(0, 1fr))"}}
 	tm.staticMappings["grid-cols-3"] = []CSSProperty{{Name: "grid-template-columns", Value: "repeat(3, minmax(0, 1fr))"}}
 	tm.staticMappings["grid-cols-4"] = []CSSProperty{{Name: "grid-template-columns", Value: "repeat(4, minmax(0, 1fr))"}}
 
-	// Focus states
-	tm.staticMappings["focus:outline-none"] = []CSSProperty{{Name: "outline", Value: "none"}}
-	tm.staticMappings["focus:ring-2"] = []CSSProperty{{Name: "box-shadow", Value: "0 0 0 2px rgba(59, 130, 246, 0.5)"}}
+	// Outline
+	tm.staticMappings["outline-none"] = []CSSProperty{{Name: "outline", Value: "none"}}
 }
 
 func (tm *TailwindMappings) initDynamicMappings() {
-	// Gap
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^gap-(\d+(?:\.\d+)?)$`),
-		Convert: func(matches []string) []CSSProperty {
-			value := tm.convertSpacing(matches[1])
-			return []CSSProperty{{Name: "gap", Value: value}}
-		},
-	})
-
-	// Padding
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^p-(\d+(?:\.\d+)?)$`),
-		Convert: func(matches []string) []CSSProperty {
-			value := tm.convertSpacing(matches[1])
-			return []CSSProperty{{Name: "padding", Value: value}}
-		},
-	})
-
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^p([xytrbl])-(\d+(?:\.\d+)?)$`),
-		Convert: func(matches []string) []CSSProperty {
-			direction := matches[1]
-			value := tm.convertSpacing(matches[2])
-			return tm.getPaddingProperties(direction, value)
-		},
-	})
-
-	// Margin
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^m-(\d+(?:\.\d+)?)$`),
-		Convert: func(matches []string) []CSSProperty {
-			value := tm.convertSpacing(matches[1])
-			return []CSSProperty{{Name: "margin", Value: value}}
-		},
-	})
-
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^m([xytrbl])-(\d+(?:\.\d+)?)$`),
-		Convert: func(matches []string) []CSSProperty {
-			direction := matches[1]
-			value := tm.convertSpacing(matches[2])
-			return tm.getMarginProperties(direction, value)
-		},
-	})
-
-	// Width
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^w-(\d+(?:\.\d+)?)$`),
-		Convert: func(matches []string) []CSSProperty {
-			value := tm.convertSize(matches[1])
-			return []CSSProperty{{Name: "width", Value: value}}
-		},
-	})
-
-	// Height
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^h-(\d+(?:\.\d+)?)$`),
-		Convert: func(matches []string) []CSSProperty {
-			value := tm.convertSize(matches[1])
-			return []CSSProperty{{Name: "height", Value: value}}
-		},
-	})
-
-	// Text Size
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^text-(xs|sm|base|lg|xl|2xl|3xl|4xl|5xl|6xl|7xl|8xl|9xl)$`),
-		Convert: func(matches []string) []CSSProperty {
-			size := tm.getTextSize(matches[1])
-			return []CSSProperty{{Name: "font-size", Value: size}}
-		},
-	})
-
-	// Background Colors (including simple colors like bg-white, bg-red)
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^bg-(\w+)-(\d+)$`),
-		Convert: func(matches []string) []CSSProperty {
-			color := tm.getColor(matches[1], matches[2])
-			return []CSSProperty{{Name: "background-color", Value: color}}
-		},
-	})
-
-	// Text Colors
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^text-(\w+)-(\d+)$`),
-		Convert: func(matches []string) []CSSProperty {
-			color := tm.getColor(matches[1], matches[2])
-			return []CSSProperty{{Name: "color", Value: color}}
-		},
+	// Text Size, with the size's line height unless leading-* sets one, or
+	// the line height of a modifier as in text-lg/7 and text-[14px]/6
+	tm.addDynamic("text", func(u *parser.ParsedUtility) []CSSProperty {
+		if u.Value == nil || u.Negative {
+			return nil
+		}
+		var size string
+		var ok bool
+		switch u.Value.Kind {
+		case parser.NamedValue:
+			size, ok = tm.getTextSize(u.Value.Text)
+		case parser.ArbitraryValue:
+			// Without a modifier the arbitrary mappings handle the value, and
+			// an untyped one like text-(--brand)/50 is a colour
+			dataType := arbitraryType(u)
+			size, ok = u.Value.Text, u.Modifier != nil && dataType != "" && dataType != "color"
+		}
+		if !ok {
+			return nil
+		}
+		props := []CSSProperty{{Name: "font-size", Value: size}}
+		if u.Modifier != nil {
+			leading := tm.lineHeight(u.Modifier)
+			if leading == "" {
+				return nil
+			}
+			return append(props, CSSProperty{Name: "line-height", Value: leading})
+		}
+		if leading, ok := tm.theme.Value("--text-" + u.Value.Text + "--line-height"); ok {
+			props = append(props, CSSProperty{Name: "line-height", Value: "var(--tw-leading, " + leading + ")"})
+		}
+		return props
 	})
 
-	// Border Colors
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^border-(\w+)-(\d+)$`),
-		Convert: func(matches []string) []CSSProperty {
-			color := tm.getColor(matches[1], matches[2])
-			return []CSSProperty{{Name: "border-color", Value: color}}
-		},
+	// Font family and weight
+	tm.addDynamic("font", func(u *parser.ParsedUtility) []CSSProperty {
+		if family, ok := tm.themeValue("font", u); ok {
+			return []CSSProperty{{Name: "font-family", Value: family}}
+		}
+		if weight, ok := tm.themeValue("font-weight", u); ok {
+			return []CSSProperty{{Name: "font-weight", Value: weight}}
+		}
+		return nil
 	})
 
-	// Grid responsive classes (md:, lg:)
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^(md|lg):grid-cols-(\d+)$`),
-		Convert: func(matches []string) []CSSProperty {
-			breakpoint := matches[1]
-			cols := matches[2]
-			mediaQuery := tm.getMediaQuery(breakpoint)
-			return []CSSProperty{{Name: "@media " + mediaQuery, Value: "grid-template-columns: repeat(" + cols + ", minmax(0, 1fr))"}}
-		},
-	})
+	// Border radius
+	for root, properties := range radiusProperties {
+		properties := properties
+		tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+			radius, ok := tm.themeValue("radius", u)
+			if !ok {
+				return nil
+			}
+			props := make([]CSSProperty, 0, len(properties))
+			for _, property := range properties {
+				props = append(props, CSSProperty{Name: property, Value: radius})
+			}
+			return props
+		})
+	}
 
-	// Hover states
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^hover:(\w+)-(\w+)-(\d+)$`),
-		Convert: func(matches []string) []CSSProperty {
-			property := matches[1]
-			colorName := matches[2]
-			shade := matches[3]
-			color := tm.getColor(colorName, shade)
-
-			var cssProperty string
-			switch property {
-			case "bg":
-				cssProperty = "background-color"
-			case "text":
-				cssProperty = "color"
-			case "border":
-				cssProperty = "border-color"
-			default:
-				cssProperty = "color"
+	// Colors
+	for root, properties := range colorProperties {
+		properties := properties
+		tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+			colors, ok := tm.utilityColor(u)
+			if !ok {
+				return nil
+			}
+			var props []CSSProperty
+			for _, color := range colors {
+				for _, property := range properties {
+					props = append(props, CSSProperty{Name: property, Value: color})
+				}
 			}
+			return props
+		})
+	}
+}
 
-			return []CSSProperty{{Name: ":hover", Value: cssProperty + ": " + color}}
-		},
-	})
+// Properties set by each colour root
+var colorProperties = map[string][]string{
+	"bg":         {"background-color"},
+	"text":       {"color"},
+	"border":     {"border-color"},
+	"border-x":   {"border-left-color", "border-right-color"},
+	"border-y":   {"border-top-color", "border-bottom-color"},
+	"border-s":   {"border-inline-start-color"},
+	"border-e":   {"border-inline-end-color"},
+	"border-t":   {"border-top-color"},
+	"border-r":   {"border-right-color"},
+	"border-b":   {"border-bottom-color"},
+	"border-l":   {"border-left-color"},
+	"outline":    {"outline-color"},
+	"decoration": {"text-decoration-color"},
+	"accent":     {"accent-color"},
+	"caret":      {"caret-color"},
+	"fill":       {"fill"},
+	"stroke":     {"stroke"},
+}
 
-	// Focus ring colors
-	tm.dynamicRegex = append(tm.dynamicRegex, &DynamicMapping{
-		Pattern: regexp.MustCompile(`^focus:ring-(\w+)-(\d+)$`),
-		Convert: func(matches []string) []CSSProperty {
-			color := tm.getColor(matches[1], matches[2])
-			return []CSSProperty{{Name: "box-shadow", Value: "0 0 0 2px " + color}}
-		},
-	})
+// Leading words of the border values that are not colours, like border-dashed
+var borderKeywords = setOf("solid", "dashed", "dotted", "double", "hidden", "none", "collapse", "separate", "spacing")
+
+// Leading words of the values each colour root takes besides colours, like
+// bg-fixed, which are not reported as unknown colours
+var nonColorKeywords = map[string]map[string]bool{
+	"bg": setOf("fixed", "local", "scroll", "clip", "origin", "repeat", "no", "auto", "cover", "contain",
+		"top", "bottom", "left", "right", "center", "none", "linear", "radial", "conic", "blend", "size", "position"),
+	"text": setOf("xs", "sm", "base", "lg", "xl", "left", "center", "right", "justify", "start", "end",
+		"wrap", "nowrap", "balance", "pretty", "ellipsis", "clip", "shadow"),
+	"border":       borderKeywords,
+	"border-x":     borderKeywords,
+	"border-y":     borderKeywords,
+	"border-s":     borderKeywords,
+	"border-e":     borderKeywords,
+	"border-t":     borderKeywords,
+	"border-r":     borderKeywords,
+	"border-b":     borderKeywords,
+	"border-l":     borderKeywords,
+	"outline":      setOf("solid", "dashed", "dotted", "double", "none", "hidden", "offset"),
+	"decoration":   setOf("solid", "dashed", "dotted", "double", "wavy", "auto", "from", "clone", "slice"),
+	"accent":       setOf("auto"),
+	"caret":        setOf("auto"),
+	"fill":         setOf("none"),
+	"stroke":       setOf("none"),
+	"shadow":       setOf("none", "xs", "sm", "md", "lg", "xl", "inner"),
+	"inset-shadow": setOf("none", "xs", "sm", "md", "lg", "xl"),
+	"ring":         setOf("inset"),
 }
 
-func (tm *TailwindMappings) convertSpacing(value string) string {
-	// Convert Tailwind spacing to rem
-	if val, err := strconv.ParseFloat(value, 64); err == nil {
-		return strconv.FormatFloat(val*0.25, 'f', -1, 64) + "rem"
+// UnknownColor reports the colour of a colour utility that did not convert,
+// e.g. "brand-500" in bg-brand-500 or "primary" in text-primary: a name the
+// theme's --color-* tokens do not define and that is not a keyword of the root
+func (tm *TailwindMappings) UnknownColor(u *parser.ParsedUtility) (string, bool) {
+	_, isColorRoot := colorProperties[u.Root]
+	_, isShadowRoot := shadowColorVariables[u.Root]
+	if !isColorRoot && !isShadowRoot && !gradientStopRoots[u.Root] {
+		return "", false
+	}
+	if u.Value == nil || u.Value.Kind != parser.NamedValue {
+		return "", false
+	}
+	value := u.Value.Text
+	if value == "" || value[0] < 'a' || value[0] > 'z' {
+		return "", false
+	}
+	if word, _, _ := strings.Cut(value, "-"); nonColorKeywords[u.Root][word] {
+		return "", false
+	}
+	if _, ok := tm.getColor(value); ok {
+		return "", false
 	}
-	return value
+	return value, true
 }
 
-func (tm *TailwindMappings) convertSize(value string) string {
-	// Convert Tailwind size to rem
-	if val, err := strconv.ParseFloat(value, 64); err == nil {
-		return strconv.FormatFloat(val*0.25, 'f', -1, 64) + "rem"
+// Properties set by each border radius root
+var radiusProperties = map[string][]string{
+	"rounded":    {"border-radius"},
+	"rounded-s":  {"border-start-start-radius", "border-end-start-radius"},
+	"rounded-e":  {"border-start-end-radius", "border-end-end-radius"},
+	"rounded-t":  {"border-top-left-radius", "border-top-right-radius"},
+	"rounded-r":  {"border-top-right-radius", "border-bottom-right-radius"},
+	"rounded-b":  {"border-bottom-right-radius", "border-bottom-left-radius"},
+	"rounded-l":  {"border-top-left-radius", "border-bottom-left-radius"},
+	"rounded-ss": {"border-start-start-radius"},
+	"rounded-se": {"border-start-end-radius"},
+	"rounded-ee": {"border-end-end-radius"},
+	"rounded-es": {"border-end-start-radius"},
+	"rounded-tl": {"border-top-left-radius"},
+	"rounded-tr": {"border-top-right-radius"},
+	"rounded-br": {"border-bottom-right-radius"},
+	"rounded-bl": {"border-bottom-left-radius"},
+}
+
+func (tm *TailwindMappings) convertSpacing(value string) string {
+	// Multiply the --spacing unit, e.g. 4 * 0.25rem = 1rem
+	unit, ok := tm.theme.Value("--spacing")
+	if !ok {
+		return ""
+	}
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return ""
+	}
+	if val == 0 {
+		return "0"
+	}
+	if match := dimensionPattern.FindStringSubmatch(unit); match != nil {
+		if base, err := strconv.ParseFloat(match[1], 64); err == nil {
+			return strconv.FormatFloat(base*val, 'f', -1, 64) + match[2]
+		}
 	}
-	return value
+	return "calc(" + unit + " * " + value + ")"
 }
 
 func (tm *TailwindMappings) getPaddingProperties(direction, value string) []CSSProperty {
@@ -323,6 +425,10 @@ func (tm *TailwindMappings) getPaddingProperties(direction, value string) []CSSP
 		return []CSSProperty{{Name: "padding-bottom", Value: value}}
 	case "l":
 		return []CSSProperty{{Name: "padding-left", Value: value}}
+	case "s":
+		return []CSSProperty{{Name: "padding-inline-start", Value: value}}
+	case "e":
+		return []CSSProperty{{Name: "padding-inline-end", Value: value}}
 	}
 	return []CSSProperty{}
 }
@@ -347,118 +453,118 @@ func (tm *TailwindMappings) getMarginProperties(direction, value string) []CSSPr
 		return []CSSProperty{{Name: "margin-bottom", Value: value}}
 	case "l":
 		return []CSSProperty{{Name: "margin-left", Value: value}}
+	case "s":
+		return []CSSProperty{{Name: "margin-inline-start", Value: value}}
+	case "e":
+		return []CSSProperty{{Name: "margin-inline-end", Value: value}}
 	}
 	return []CSSProperty{}
 }
 
-func (tm *TailwindMappings) getTextSize(size string) string {
-	sizes := map[string]string{
-		"xs":   "0.75rem",
-		"sm":   "0.875rem",
-		"base": "1rem",
-		"lg":   "1.125rem",
-		"xl":   "1.25rem",
-		"2xl":  "1.5rem",
-		"3xl":  "1.875rem",
-		"4xl":  "2.25rem",
-		"5xl":  "3rem",
-		"6xl":  "3.75rem",
-		"7xl":  "4.5rem",
-		"8xl":  "6rem",
-		"9xl":  "8rem",
-	}
-
-	if val, exists := sizes[size]; exists {
-		return val
-	}
-	return "1rem"
+func (tm *TailwindMappings) getTextSize(size string) (string, bool) {
+	if size == "" {
+		return "", false
+	}
+	return tm.theme.Value("--text-" + size)
 }
 
-func (tm *TailwindMappings) getColor(colorName, shade string) string {
-	// Comprehensive color mapping
-	colors := map[string]map[string]string{
-		"blue": {
-			"50":  "#eff6ff",
-			"100": "#dbeafe",
-			"200": "#bfdbfe",
-			"300": "#93c5fd",
-			"400": "#60a5fa",
-			"500": "#3b82f6",
-			"600": "#2563eb",
-			"700": "#1d4ed8",
-			"800": "#1e40af",
-			"900": "#1e3a8a",
-		},
-		"red": {
-			"50":  "#fef2f2",
-			"100": "#fee2e2",
-			"200": "#fecaca",
-			"300": "#fca5a5",
-			"400": "#f87171",
-			"500": "#ef4444",
-			"600": "#dc2626",
-			"700": "#b91c1c",
-			"800": "#991b1b",
-			"900": "#7f1d1d",
-		},
-		"green": {
-			"50":  "#f0fdf4",
-			"100": "#dcfce7",
-			"200": "#bbf7d0",
-			"300": "#86efac",
-			"400": "#4ade80",
-			"500": "#22c55e",
-			"600": "#16a34a",
-			"700": "#15803d",
-			"800": "#166534",
-			"900": "#14532d",
-		},
-		"gray": {
-			"50":  "#f9fafb",
-			"100": "#f3f4f6",
-			"200": "#e5e7eb",
-			"300": "#d1d5db",
-			"400": "#9ca3af",
-			"500": "#6b7280",
-			"600": "#4b5563",
-			"700": "#374151",
-			"800": "#1f2937",
-			"900": "#111827",
-		},
-		"purple": {
-			"50":  "#faf5ff",
-			"100": "#f3e8ff",
-			"200": "#e9d5ff",
-			"300": "#d8b4fe",
-			"400": "#c084fc",
-			"500": "#a855f7",
-			"600": "#9333ea",
-			"700": "#7c3aed",
-			"800": "#6b21a8",
-			"900": "#581c87",
-		},
-	}
-
-	if colorMap, exists := colors[colorName]; exists {
-		if color, exists := colorMap[shade]; exists {
-			return color
+// getColor resolves a colour name like blue-500 from the theme, written in
+// the configured colour format
+func (tm *TailwindMappings) getColor(color string) (string, bool) {
+	if color == "" {
+		return "", false
+	}
+	if special, exists := specialColors[color]; exists {
+		return special, true
+	}
+
+	name := "--color-" + color
+	value, ok := tm.theme.Value(name)
+	if !ok {
+		return "", false
+	}
+	if tm.colorFormat == ColorVar {
+		if token, _ := tm.theme.Token(name); !token.Inline {
+			return "var(" + name + ")", true
+		}
+	}
+	return formatColor(value, tm.colorFormat), true
+}
+
+// utilityColor resolves the colour of a colour utility: a theme colour like
+// blue-500, or an arbitrary colour like [#1da1f2]. An opacity modifier, as in
+// bg-blue-500/50, mixes the colour with transparent the way v4 does. With
+// fallbacks enabled a precomputed rgba() comes first for older browsers.
+func (tm *TailwindMappings) utilityColor(u *parser.ParsedUtility) ([]string, bool) {
+	if u.Value == nil || u.Negative {
+		return nil, false
+	}
+
+	var color, resolved string
+	switch u.Value.Kind {
+	case parser.NamedValue:
+		var ok bool
+		if color, ok = tm.getColor(u.Value.Text); !ok {
+			return nil, false
+		}
+		resolved, _ = tm.theme.Value("--color-" + u.Value.Text)
+	case parser.ArbitraryValue:
+		if dataType := arbitraryType(u); dataType != "color" && dataType != "" {
+			return nil, false
 		}
+		color, resolved = u.Value.Text, u.Value.Text
 	}
 
-	return "#000000" // fallback
+	if u.Modifier == nil {
+		return []string{color}, true
+	}
+	if color == "inherit" {
+		return nil, false
+	}
+	if color == "transparent" {
+		return []string{color}, true
+	}
+
+	opacity, fraction, ok := opacityValue(u.Modifier)
+	if !ok {
+		return nil, false
+	}
+	mixed := "color-mix(in oklab, " + color + " " + opacity + ", transparent)"
+
+	if tm.colorFallback && fraction >= 0 {
+		if rgb, ok := parseColor(resolved); ok {
+			rgb.a *= fraction
+			return []string{rgb.rgba(), mixed}, true
+		}
+	}
+	return []string{mixed}, true
 }
 
-func (tm *TailwindMappings) getMediaQuery(breakpoint string) string {
-	breakpoints := map[string]string{
-		"sm":  "(min-width: 640px)",
-		"md":  "(min-width: 768px)",
-		"lg":  "(min-width: 1024px)",
-		"xl":  "(min-width: 1280px)",
-		"2xl": "(min-width: 1536px)",
+// opacityValue reads an opacity modifier like 50, [0.15], [15%] or
+// (--my-opacity) as a percentage. The fraction is -1 when it is not static.
+func opacityValue(modifier *parser.UtilityValue) (string, float64, bool) {
+	text := modifier.Text
+	if modifier.Kind == parser.ArbitraryValue {
+		if strings.HasPrefix(text, "var(") {
+			return text, -1, true
+		}
+		if strings.HasSuffix(text, "%") {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+			return text, n / 100, err == nil
+		}
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return "", 0, false
+		}
+		if n <= 1 {
+			n *= 100
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64) + "%", n / 100, true
 	}
 
-	if query, exists := breakpoints[breakpoint]; exists {
-		return query
+	if !numberPattern.MatchString(text) {
+		return "", 0, false
 	}
-	return "(min-width: 768px)" // fallback to md
+	n, _ := strconv.ParseFloat(text, 64)
+	return text + "%", n / 100, true
 }
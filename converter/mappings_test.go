@@ -0,0 +1,43 @@
+package converter
+
+import (
+	"testing"
+
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+func TestUnknownColor(t *testing.T) {
+	tests := []struct {
+		class   string
+		color   string
+		unknown bool
+	}{
+		{"bg-primary", "primary", true},
+		{"bg-blue", "blue", true},
+		{"text-blue-1000", "blue-1000", true},
+		{"border-brand-light", "brand-light", true},
+		{"from-brand", "brand", true},
+		{"shadow-brand", "brand", true},
+		{"bg-blue-500", "", false},
+		{"text-transparent", "", false},
+		{"bg-[red]", "", false},
+		{"border-2", "", false},
+		{"border-dashed", "", false},
+		{"bg-fixed", "", false},
+		{"text-ellipsis", "", false},
+		{"fill-none", "", false},
+		{"p-brand", "", false},
+	}
+
+	tm := NewTailwindMappings(DefaultOptions())
+	for _, tt := range tests {
+		u, err := parser.ParseUtility(tt.class)
+		if err != nil {
+			t.Fatalf("ParseUtility(%q): %v", tt.class, err)
+		}
+		color, unknown := tm.UnknownColor(u)
+		if color != tt.color || unknown != tt.unknown {
+			t.Errorf("UnknownColor(%s) = %q, %v, want %q, %v", tt.class, color, unknown, tt.color, tt.unknown)
+		}
+	}
+}
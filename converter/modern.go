@@ -1,8 +1,8 @@
 package converter
 
 import (
-	"regexp"
 	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
 )
 
 type ModernFeatures struct {
@@ -22,41 +22,37 @@ func NewModernFeatures() *ModernFeatures {
 	return mf
 }
 
-func (mf *ModernFeatures) Convert(class string) []CSSProperty {
-	// Handle container queries
-	if strings.HasPrefix(class, "@container") {
-		return mf.convertContainerQuery(class)
+// Convert returns the declarations of a utility, ignoring its variants
+func (mf *ModernFeatures) Convert(u *parser.ParsedUtility) []CSSProperty {
+	// Handle container markers, @container and @container/sidebar
+	if u.Root == "@container" {
+		return mf.convertContainer(u)
 	}
 
 	// Handle cascade layers
-	if strings.HasPrefix(class, "@layer") {
-		return mf.convertCascadeLayer(class)
+	if strings.HasPrefix(u.Root, "@layer") {
+		return mf.convertCascadeLayer(u.Utility)
 	}
 
-	// Handle custom properties (CSS variables)
-	if strings.Contains(class, "--") {
-		return mf.convertCustomProperty(class)
-	}
-
-	// Handle modern pseudo-classes
-	if strings.Contains(class, ":") {
-		return mf.convertModernPseudo(class)
+	// Handle arbitrary properties and custom properties (CSS variables)
+	if u.Property != "" {
+		return mf.convertArbitraryProperty(u)
 	}
 
 	// Handle new Tailwind v4+ utilities
-	return mf.convertV4Utilities(class)
+	return mf.convertV4Utilities(u)
 }
 
 func (mf *ModernFeatures) initModernFeatures() {
-	// Initialize container query mappings
-	mf.containerQueries["@container-sm"] = []CSSProperty{
-		{Name: "@container", Value: "(min-width: 640px)"},
+	// Initialize container mappings, queried by the @sm: ... @7xl: variants
+	mf.containerQueries["@container"] = []CSSProperty{
+		{Name: "container-type", Value: "inline-size"},
 	}
-	mf.containerQueries["@container-md"] = []CSSProperty{
-		{Name: "@container", Value: "(min-width: 768px)"},
+	mf.containerQueries["@container-normal"] = []CSSProperty{
+		{Name: "container-type", Value: "normal"},
 	}
-	mf.containerQueries["@container-lg"] = []CSSProperty{
-		{Name: "@container", Value: "(min-width: 1024px)"},
+	mf.containerQueries["@container-size"] = []CSSProperty{
+		{Name: "container-type", Value: "size"},
 	}
 
 	// Initialize cascade layer mappings
@@ -71,23 +67,20 @@ func (mf *ModernFeatures) initModernFeatures() {
 	}
 }
 
-func (mf *ModernFeatures) convertContainerQuery(class string) []CSSProperty {
-	// Extract container query logic
-	if strings.HasPrefix(class, "@container") {
-		// Parse container query syntax
-		parts := strings.Split(class, ":")
-		if len(parts) > 1 {
-			query := parts[0]
-			property := parts[1]
+func (mf *ModernFeatures) convertContainer(u *parser.ParsedUtility) []CSSProperty {
+	container, exists := mf.containerQueries[u.Base()]
+	if !exists || u.Negative {
+		return []CSSProperty{}
+	}
 
-			return []CSSProperty{
-				{Name: "/* Container Query */", Value: query},
-				{Name: "/* Applied when */", Value: property},
-			}
+	// A named container, @container/sidebar, is queried by @lg/sidebar:
+	if u.Modifier != nil {
+		if u.Modifier.Kind != parser.NamedValue {
+			return []CSSProperty{}
 		}
+		return append(container[:len(container):len(container)], CSSProperty{Name: "container-name", Value: u.Modifier.Text})
 	}
-
-	return []CSSProperty{}
+	return container
 }
 
 func (mf *ModernFeatures) convertCascadeLayer(class string) []CSSProperty {
@@ -101,166 +94,86 @@ func (mf *ModernFeatures) convertCascadeLayer(class string) []CSSProperty {
 	}
 }
 
-func (mf *ModernFeatures) convertCustomProperty(class string) []CSSProperty {
-	// Handle CSS custom properties
-	if strings.Contains(class, "--") {
-		parts := strings.Split(class, "-")
-		if len(parts) >= 3 {
-			varName := strings.Join(parts[2:], "-")
-			return []CSSProperty{
-				{Name: "--" + varName, Value: "/* Custom property value */"},
-			}
-		}
-	}
-
-	return []CSSProperty{}
-}
-
-func (mf *ModernFeatures) convertModernPseudo(class string) []CSSProperty {
-	// Handle modern pseudo-classes and variants
-	parts := strings.Split(class, ":")
-	if len(parts) < 2 {
-		return []CSSProperty{}
-	}
-
-	variant := parts[0]
-	baseClass := strings.Join(parts[1:], ":")
-
-	switch variant {
-	case "hover":
-		return []CSSProperty{
-			{Name: "/* On hover */", Value: baseClass},
-		}
-	case "focus":
-		return []CSSProperty{
-			{Name: "/* On focus */", Value: baseClass},
-		}
-	case "active":
-		return []CSSProperty{
-			{Name: "/* When active */", Value: baseClass},
-		}
-	case "disabled":
-		return []CSSProperty{
-			{Name: "/* When disabled */", Value: baseClass},
-		}
-	case "group-hover":
-		return []CSSProperty{
-			{Name: "/* When parent group hovered */", Value: baseClass},
-		}
-	case "peer-focus":
-		return []CSSProperty{
-			{Name: "/* When peer focused */", Value: baseClass},
-		}
-	default:
-		// Handle responsive variants
-		if mf.isResponsiveVariant(variant) {
-			return []CSSProperty{
-				{Name: "/* Responsive: " + variant + " */", Value: baseClass},
-			}
-		}
-	}
-
+func (mf *ModernFeatures) convertArbitraryProperty(u *parser.ParsedUtility) []CSSProperty {
+	// Handle arbitrary properties, [mask-type:luminance] or [--header-height:4rem]
 	return []CSSProperty{
-		{Name: "/* Unknown variant: " + variant + " */", Value: baseClass},
+		{Name: u.Property, Value: u.Value.Text},
 	}
 }
 
-func (mf *ModernFeatures) convertV4Utilities(class string) []CSSProperty {
+func (mf *ModernFeatures) convertV4Utilities(u *parser.ParsedUtility) []CSSProperty {
 	// Handle new Tailwind v4+ utilities
-	v4Patterns := map[string]func(string) []CSSProperty{
-		"size-":    mf.convertSizeUtility,
-		"grid-":    mf.convertGridUtility,
-		"place-":   mf.convertPlaceUtility,
-		"content-": mf.convertContentUtility,
+	v4Roots := map[string]func(*parser.ParsedUtility) []CSSProperty{
+		"grid-cols":     mf.convertGridUtility,
+		"grid-rows":     mf.convertGridUtility,
+		"place-content": mf.convertPlaceUtility,
+		"place-items":   mf.convertPlaceUtility,
+		"content":       mf.convertContentUtility,
 	}
 
-	for prefix, converter := range v4Patterns {
-		if strings.HasPrefix(class, prefix) {
-			return converter(class)
-		}
+	if converter, exists := v4Roots[u.Root]; exists {
+		return converter(u)
 	}
 
 	return []CSSProperty{}
 }
 
-func (mf *ModernFeatures) convertSizeUtility(class string) []CSSProperty {
-	// Handle size-* utilities (sets both width and height)
-	re := regexp.MustCompile(`^size-(\d+(?:\.\d+)?)$`)
-	if matches := re.FindStringSubmatch(class); matches != nil {
-		value := matches[1] + "rem"
-		return []CSSProperty{
-			{Name: "width", Value: value},
-			{Name: "height", Value: value},
-		}
+func (mf *ModernFeatures) convertGridUtility(u *parser.ParsedUtility) []CSSProperty {
+	// Handle advanced grid utilities
+	count := numericValue(u)
+	if count == "" || strings.Contains(count, ".") {
+		return []CSSProperty{}
 	}
-	return []CSSProperty{}
-}
 
-func (mf *ModernFeatures) convertGridUtility(class string) []CSSProperty {
-	// Handle advanced grid utilities
-	switch {
-	case strings.HasPrefix(class, "grid-cols-"):
-		re := regexp.MustCompile(`^grid-cols-(\d+)$`)
-		if matches := re.FindStringSubmatch(class); matches != nil {
-			return []CSSProperty{
-				{Name: "grid-template-columns", Value: "repeat(" + matches[1] + ", minmax(0, 1fr))"},
-			}
+	switch u.Root {
+	case "grid-cols":
+		return []CSSProperty{
+			{Name: "grid-template-columns", Value: "repeat(" + count + ", minmax(0, 1fr))"},
 		}
-	case strings.HasPrefix(class, "grid-rows-"):
-		re := regexp.MustCompile(`^grid-rows-(\d+)$`)
-		if matches := re.FindStringSubmatch(class); matches != nil {
-			return []CSSProperty{
-				{Name: "grid-template-rows", Value: "repeat(" + matches[1] + ", minmax(0, 1fr))"},
-			}
+	case "grid-rows":
+		return []CSSProperty{
+			{Name: "grid-template-rows", Value: "repeat(" + count + ", minmax(0, 1fr))"},
 		}
 	}
 	return []CSSProperty{}
 }
 
-func (mf *ModernFeatures) convertPlaceUtility(class string) []CSSProperty {
+func (mf *ModernFeatures) convertPlaceUtility(u *parser.ParsedUtility) []CSSProperty {
 	// Handle place-* utilities
-	switch class {
-	case "place-content-center":
-		return []CSSProperty{{Name: "place-content", Value: "center"}}
-	case "place-content-start":
-		return []CSSProperty{{Name: "place-content", Value: "start"}}
-	case "place-content-end":
-		return []CSSProperty{{Name: "place-content", Value: "end"}}
-	case "place-items-center":
-		return []CSSProperty{{Name: "place-items", Value: "center"}}
-	case "place-items-start":
-		return []CSSProperty{{Name: "place-items", Value: "start"}}
-	case "place-items-end":
-		return []CSSProperty{{Name: "place-items", Value: "end"}}
+	switch namedValue(u) {
+	case "center", "start", "end":
+		return []CSSProperty{{Name: u.Root, Value: namedValue(u)}}
 	}
 	return []CSSProperty{}
 }
 
-func (mf *ModernFeatures) convertContentUtility(class string) []CSSProperty {
+func (mf *ModernFeatures) convertContentUtility(u *parser.ParsedUtility) []CSSProperty {
+	// Handle generated content, content-['*'] and content-none, through
+	// --tw-content so before: and after: pick it up
+	if value := arbitraryValue(u); value != "" || namedValue(u) == "none" {
+		if value == "" {
+			value = "none"
+		}
+		return []CSSProperty{
+			{Name: "--tw-content", Value: value},
+			{Name: "content", Value: "var(--tw-content)"},
+		}
+	}
+
 	// Handle content-* utilities
-	switch class {
-	case "content-center":
+	switch namedValue(u) {
+	case "center":
 		return []CSSProperty{{Name: "align-content", Value: "center"}}
-	case "content-start":
+	case "start":
 		return []CSSProperty{{Name: "align-content", Value: "flex-start"}}
-	case "content-end":
+	case "end":
 		return []CSSProperty{{Name: "align-content", Value: "flex-end"}}
-	case "content-between":
+	case "between":
 		return []CSSProperty{{Name: "align-content", Value: "space-between"}}
-	case "content-around":
+	case "around":
 		return []CSSProperty{{Name: "align-content", Value: "space-around"}}
-	case "content-evenly":
+	case "evenly":
 		return []CSSProperty{{Name: "align-content", Value: "space-evenly"}}
 	}
 	return []CSSProperty{}
 }
-
-func (mf *ModernFeatures) isResponsiveVariant(variant string) bool {
-	responsiveVariants := []string{"sm", "md", "lg", "xl", "2xl"}
-	for _, rv := range responsiveVariants {
-		if variant == rv {
-			return true
-		}
-	}
-	return false
-}
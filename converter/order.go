@@ -0,0 +1,336 @@
+package converter
+
+import "sort"
+
+// propertyOrder lists properties in the order Tailwind emits the utilities
+// setting them. Shorthands come before their longhands, so p-4 px-2 keeps
+// the inline padding whatever the order of the classes.
+var propertyOrder = []string{
+	"container-type",
+	"pointer-events",
+	"visibility",
+	"position",
+
+	"inset",
+	"inset-inline",
+	"inset-block",
+	"inset-inline-start",
+	"inset-inline-end",
+	"top",
+	"right",
+	"bottom",
+	"left",
+
+	"isolation",
+	"z-index",
+	"order",
+	"grid-column",
+	"grid-column-start",
+	"grid-column-end",
+	"grid-row",
+	"grid-row-start",
+	"grid-row-end",
+	"float",
+	"clear",
+
+	"margin",
+	"margin-inline",
+	"margin-block",
+	"margin-inline-start",
+	"margin-inline-end",
+	"margin-block-start",
+	"margin-block-end",
+	"margin-top",
+	"margin-right",
+	"margin-bottom",
+	"margin-left",
+
+	"box-sizing",
+	"display",
+	"aspect-ratio",
+
+	"height",
+	"max-height",
+	"min-height",
+	"width",
+	"max-width",
+	"min-width",
+
+	"flex",
+	"flex-shrink",
+	"flex-grow",
+	"flex-basis",
+
+	"table-layout",
+	"caption-side",
+	"border-collapse",
+	"border-spacing",
+
+	"transform-origin",
+	"translate",
+	"--tw-translate-x",
+	"--tw-translate-y",
+	"--tw-translate-z",
+	"scale",
+	"--tw-scale-x",
+	"--tw-scale-y",
+	"--tw-scale-z",
+	"rotate",
+	"--tw-rotate-x",
+	"--tw-rotate-y",
+	"--tw-rotate-z",
+	"--tw-skew-x",
+	"--tw-skew-y",
+	"transform",
+	"transform-style",
+	"backface-visibility",
+	"perspective",
+	"perspective-origin",
+
+	"animation",
+	"cursor",
+	"touch-action",
+	"resize",
+
+	"scroll-snap-type",
+	"scroll-snap-align",
+	"scroll-snap-stop",
+	"scroll-margin",
+	"scroll-padding",
+
+	"list-style-position",
+	"list-style-type",
+	"list-style-image",
+
+	"appearance",
+	"columns",
+	"break-before",
+	"break-inside",
+	"break-after",
+
+	"grid-auto-columns",
+	"grid-template-columns",
+	"grid-auto-flow",
+	"grid-auto-rows",
+	"grid-template-rows",
+
+	"flex-direction",
+	"flex-wrap",
+	"place-content",
+	"place-items",
+	"align-content",
+	"align-items",
+	"justify-content",
+	"justify-items",
+
+	"gap",
+	"column-gap",
+	"row-gap",
+
+	"--tw-space-x-reverse",
+	"--tw-space-y-reverse",
+	"--tw-divide-x-reverse",
+	"--tw-divide-y-reverse",
+
+	"place-self",
+	"align-self",
+	"justify-self",
+
+	"overflow",
+	"overflow-x",
+	"overflow-y",
+	"overscroll-behavior",
+	"overscroll-behavior-x",
+	"overscroll-behavior-y",
+	"scroll-behavior",
+
+	"border-radius",
+	"border-start-start-radius",
+	"border-start-end-radius",
+	"border-end-end-radius",
+	"border-end-start-radius",
+	"border-top-left-radius",
+	"border-top-right-radius",
+	"border-bottom-right-radius",
+	"border-bottom-left-radius",
+
+	"border",
+	"border-inline",
+	"border-block",
+	"border-inline-start",
+	"border-inline-end",
+	"border-top",
+	"border-right",
+	"border-bottom",
+	"border-left",
+
+	"border-width",
+	"border-inline-width",
+	"border-block-width",
+	"border-inline-start-width",
+	"border-inline-end-width",
+	"border-top-width",
+	"border-right-width",
+	"border-bottom-width",
+	"border-left-width",
+
+	"border-style",
+	"border-inline-style",
+	"border-block-style",
+	"border-inline-start-style",
+	"border-inline-end-style",
+	"border-top-style",
+	"border-right-style",
+	"border-bottom-style",
+	"border-left-style",
+
+	"border-color",
+	"border-inline-color",
+	"border-block-color",
+	"border-inline-start-color",
+	"border-inline-end-color",
+	"border-top-color",
+	"border-right-color",
+	"border-bottom-color",
+	"border-left-color",
+
+	"background",
+	"background-color",
+	"background-image",
+	"--tw-gradient-position",
+	"--tw-gradient-stops",
+	"--tw-gradient-via-stops",
+	"--tw-gradient-from",
+	"--tw-gradient-from-position",
+	"--tw-gradient-via",
+	"--tw-gradient-via-position",
+	"--tw-gradient-to",
+	"--tw-gradient-to-position",
+
+	"box-decoration-break",
+	"background-size",
+	"background-attachment",
+	"background-clip",
+	"background-position",
+	"background-repeat",
+	"background-origin",
+
+	"fill",
+	"stroke",
+	"stroke-width",
+
+	"object-fit",
+	"object-position",
+
+	"padding",
+	"padding-inline",
+	"padding-block",
+	"padding-inline-start",
+	"padding-inline-end",
+	"padding-block-start",
+	"padding-block-end",
+	"padding-top",
+	"padding-right",
+	"padding-bottom",
+	"padding-left",
+
+	"text-align",
+	"text-indent",
+	"vertical-align",
+	"font-family",
+	"font-size",
+	"line-height",
+	"font-weight",
+	"letter-spacing",
+	"text-wrap",
+	"overflow-wrap",
+	"word-break",
+	"text-overflow",
+	"hyphens",
+	"white-space",
+	"color",
+	"text-transform",
+	"font-style",
+	"font-stretch",
+	"font-variant-numeric",
+	"text-decoration",
+	"text-decoration-line",
+	"text-decoration-color",
+	"text-decoration-style",
+	"text-decoration-thickness",
+	"text-underline-offset",
+	"-webkit-font-smoothing",
+
+	"caret-color",
+	"accent-color",
+	"color-scheme",
+	"opacity",
+	"background-blend-mode",
+	"mix-blend-mode",
+
+	"box-shadow",
+	"--tw-shadow",
+	"--tw-shadow-color",
+	"--tw-ring-shadow",
+	"--tw-ring-color",
+	"--tw-inset-shadow",
+	"--tw-inset-shadow-color",
+	"--tw-inset-ring-shadow",
+	"--tw-inset-ring-color",
+	"--tw-ring-offset-width",
+	"--tw-ring-offset-color",
+
+	"outline",
+	"outline-width",
+	"outline-offset",
+	"outline-color",
+
+	"filter",
+	"backdrop-filter",
+
+	"transition-property",
+	"transition-behavior",
+	"transition-delay",
+	"transition-duration",
+	"transition-timing-function",
+	"will-change",
+	"contain",
+	"content",
+	"forced-color-adjust",
+}
+
+var propertyIndex = func() map[string]int {
+	index := make(map[string]int, len(propertyOrder))
+	for i, name := range propertyOrder {
+		index[name] = i
+	}
+	return index
+}()
+
+// propertySort returns the positions in propertyOrder of the properties a
+// class sets, ascending; properties missing from the order are ignored
+func propertySort(props []CSSProperty) []int {
+	var positions []int
+	for _, prop := range props {
+		if i, known := propertyIndex[prop.Name]; known {
+			positions = append(positions, i)
+		}
+	}
+	sort.Ints(positions)
+	return positions
+}
+
+// propertyLess orders two classes like Tailwind: by their first differing
+// property, then the class setting fewer properties first. Classes setting
+// no known property come last.
+func propertyLess(a, b []int) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(b) == 0 && len(a) != 0
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
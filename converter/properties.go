@@ -0,0 +1,128 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// registeredProperty is the @property rule of a --tw-* variable. Registering
+// the variables gives them an initial value, so a declaration composing
+// several of them works when utilities set only some.
+type registeredProperty struct {
+	syntax       string
+	initialValue string
+	inherits     bool
+}
+
+var registeredProperties = map[string]registeredProperty{
+	"--tw-content":          {syntax: `"*"`, initialValue: `""`},
+	"--tw-border-style":     {syntax: `"*"`, initialValue: "solid"},
+	"--tw-space-x-reverse":  {syntax: `"*"`, initialValue: "0"},
+	"--tw-space-y-reverse":  {syntax: `"*"`, initialValue: "0"},
+	"--tw-divide-x-reverse": {syntax: `"*"`, initialValue: "0"},
+	"--tw-divide-y-reverse": {syntax: `"*"`, initialValue: "0"},
+
+	// Transforms
+	"--tw-translate-x": {syntax: `"*"`, initialValue: "0"},
+	"--tw-translate-y": {syntax: `"*"`, initialValue: "0"},
+	"--tw-translate-z": {syntax: `"*"`, initialValue: "0"},
+	"--tw-scale-x":     {syntax: `"*"`, initialValue: "1"},
+	"--tw-scale-y":     {syntax: `"*"`, initialValue: "1"},
+	"--tw-scale-z":     {syntax: `"*"`, initialValue: "1"},
+	"--tw-rotate-x":    {syntax: `"*"`},
+	"--tw-rotate-y":    {syntax: `"*"`},
+	"--tw-rotate-z":    {syntax: `"*"`},
+	"--tw-skew-x":      {syntax: `"*"`},
+	"--tw-skew-y":      {syntax: `"*"`},
+
+	// Shadows and rings
+	"--tw-shadow":             {syntax: `"*"`, initialValue: "0 0 #0000"},
+	"--tw-shadow-color":       {syntax: `"*"`},
+	"--tw-inset-shadow":       {syntax: `"*"`, initialValue: "0 0 #0000"},
+	"--tw-inset-shadow-color": {syntax: `"*"`},
+	"--tw-ring-color":         {syntax: `"*"`},
+	"--tw-ring-shadow":        {syntax: `"*"`, initialValue: "0 0 #0000"},
+	"--tw-inset-ring-color":   {syntax: `"*"`},
+	"--tw-inset-ring-shadow":  {syntax: `"*"`, initialValue: "0 0 #0000"},
+	"--tw-ring-inset":         {syntax: `"*"`},
+	"--tw-ring-offset-width":  {syntax: `"<length>"`, initialValue: "0px"},
+	"--tw-ring-offset-color":  {syntax: `"*"`, initialValue: "#fff"},
+	"--tw-ring-offset-shadow": {syntax: `"*"`, initialValue: "0 0 #0000"},
+
+	// Gradients
+	"--tw-gradient-position":      {syntax: `"*"`},
+	"--tw-gradient-from":          {syntax: `"<color>"`, initialValue: "#0000"},
+	"--tw-gradient-via":           {syntax: `"<color>"`, initialValue: "#0000"},
+	"--tw-gradient-to":            {syntax: `"<color>"`, initialValue: "#0000"},
+	"--tw-gradient-stops":         {syntax: `"*"`},
+	"--tw-gradient-via-stops":     {syntax: `"*"`},
+	"--tw-gradient-from-position": {syntax: `"<length-percentage>"`, initialValue: "0%"},
+	"--tw-gradient-via-position":  {syntax: `"<length-percentage>"`, initialValue: "50%"},
+	"--tw-gradient-to-position":   {syntax: `"<length-percentage>"`, initialValue: "100%"},
+
+	// Transition timing
+	"--tw-duration": {syntax: `"*"`},
+	"--tw-ease":     {syntax: `"*"`},
+
+	// Typography
+	"--tw-leading":          {syntax: `"*"`},
+	"--tw-tracking":         {syntax: `"*"`},
+	"--tw-ordinal":          {syntax: `"*"`},
+	"--tw-slashed-zero":     {syntax: `"*"`},
+	"--tw-numeric-figure":   {syntax: `"*"`},
+	"--tw-numeric-spacing":  {syntax: `"*"`},
+	"--tw-numeric-fraction": {syntax: `"*"`},
+}
+
+func init() {
+	// Filter functions, without an initial value so unset ones drop out of
+	// the composed filter
+	for _, function := range filterFunctions {
+		if !function.backdrop {
+			registeredProperties["--tw-"+function.name] = registeredProperty{syntax: `"*"`}
+		}
+		registeredProperties["--tw-backdrop-"+function.name] = registeredProperty{syntax: `"*"`}
+	}
+	registeredProperties["--tw-drop-shadow"] = registeredProperty{syntax: `"*"`}
+}
+
+var twVariablePattern = regexp.MustCompile(`--tw-[a-z0-9-]+`)
+
+// propertyRules returns the @property rules for the registered variables the
+// rules set or read, in order of first use
+func propertyRules(rules []CSSRule) []CSSRule {
+	seen := make(map[string]bool)
+	var properties []CSSRule
+
+	for _, rule := range rules {
+		for _, prop := range rule.Properties {
+			if strings.HasPrefix(prop.Name, "/*") {
+				continue
+			}
+			for _, name := range twVariablePattern.FindAllString(prop.Name+" "+prop.Value, -1) {
+				registered, exists := registeredProperties[name]
+				if !exists || seen[name] {
+					continue
+				}
+				seen[name] = true
+				properties = append(properties, registered.rule(name))
+			}
+		}
+	}
+
+	return properties
+}
+
+func (p registeredProperty) rule(name string) CSSRule {
+	props := []CSSProperty{{Name: "syntax", Value: p.syntax}}
+	if p.initialValue != "" {
+		props = append(props, CSSProperty{Name: "initial-value", Value: p.initialValue})
+	}
+	inherits := "false"
+	if p.inherits {
+		inherits = "true"
+	}
+	props = append(props, CSSProperty{Name: "inherits", Value: inherits})
+
+	return CSSRule{Selector: "@property " + name, Properties: props}
+}
@@ -0,0 +1,164 @@
+package converter
+
+import (
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// boxShadowValue layers the shadows and rings of an element the way v4 does.
+// Each utility sets one --tw-* layer, so shadow-md, ring-2 and
+// focus:ring-blue-500 on one element all show.
+const boxShadowValue = "var(--tw-inset-shadow), var(--tw-inset-ring-shadow), var(--tw-ring-offset-shadow), var(--tw-ring-shadow), var(--tw-shadow)"
+
+// Variables set by the colour utilities of each shadow and ring root
+var shadowColorVariables = map[string]string{
+	"shadow":       "--tw-shadow-color",
+	"inset-shadow": "--tw-inset-shadow-color",
+	"ring":         "--tw-ring-color",
+	"inset-ring":   "--tw-inset-ring-color",
+	"ring-offset":  "--tw-ring-offset-color",
+}
+
+func (tm *TailwindMappings) initShadowMappings() {
+	// Shadows from the theme, shadow-md and inset-shadow-sm, and their colours
+	tm.addShadow("shadow", "--tw-shadow", "shadow")
+	tm.addShadow("inset-shadow", "--tw-inset-shadow", "inset-shadow")
+
+	// Rings, ring-2 and inset-ring, outside or inside the border
+	tm.addDynamic("ring", func(u *parser.ParsedUtility) []CSSProperty {
+		if namedValue(u) == "inset" {
+			return []CSSProperty{{Name: "--tw-ring-inset", Value: "inset"}}
+		}
+		if width := ringWidth(u); width != "" {
+			return []CSSProperty{
+				{Name: "--tw-ring-shadow", Value: "var(--tw-ring-inset,) 0 0 0 calc(" + width + " + var(--tw-ring-offset-width)) var(--tw-ring-color, currentcolor)"},
+				{Name: "box-shadow", Value: boxShadowValue},
+			}
+		}
+		return tm.shadowColor(u)
+	})
+	tm.addDynamic("inset-ring", func(u *parser.ParsedUtility) []CSSProperty {
+		if width := ringWidth(u); width != "" {
+			return []CSSProperty{
+				{Name: "--tw-inset-ring-shadow", Value: "inset 0 0 0 " + width + " var(--tw-inset-ring-color, currentcolor)"},
+				{Name: "box-shadow", Value: boxShadowValue},
+			}
+		}
+		return tm.shadowColor(u)
+	})
+
+	// Ring offsets, a solid ring between the element and its ring
+	tm.addDynamic("ring-offset", func(u *parser.ParsedUtility) []CSSProperty {
+		if u.Value != nil {
+			if width := ringWidth(u); width != "" {
+				return []CSSProperty{
+					{Name: "--tw-ring-offset-width", Value: width},
+					{Name: "--tw-ring-offset-shadow", Value: "var(--tw-ring-inset,) 0 0 0 var(--tw-ring-offset-width) var(--tw-ring-offset-color)"},
+				}
+			}
+		}
+		return tm.shadowColor(u)
+	})
+}
+
+// ringWidth returns the width of a ring utility like ring-2; an untyped
+// bracketed value, ring-(--x), is a colour instead
+func ringWidth(u *parser.ParsedUtility) string {
+	if u.Value != nil && u.Value.Kind == parser.ArbitraryValue && arbitraryType(u) == "" {
+		return ""
+	}
+	return borderWidth(u)
+}
+
+// addShadow registers a shadow root: a theme shadow whose colours can be
+// replaced with the root's colour utilities, none, or a colour
+func (tm *TailwindMappings) addShadow(root, variable, namespace string) {
+	colorVariable := shadowColorVariables[root]
+	tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+		var shadow string
+		switch value := namedValue(u); {
+		case u.Value == nil && root == "shadow" && !u.Negative && u.Modifier == nil:
+			// The bare shadow is the small one
+			shadow, _ = tm.theme.Value("--" + namespace + "-sm")
+		case value == "none":
+			return []CSSProperty{
+				{Name: variable, Value: "0 0 #0000"},
+				{Name: "box-shadow", Value: boxShadowValue},
+			}
+		case value != "":
+			shadow, _ = tm.themeValue(namespace, u)
+		default:
+			if value := arbitraryValue(u); value != "" && u.Value.DataType != "color" && inferDataType(value) != "color" {
+				shadow = value
+			}
+		}
+
+		if shadow == "" {
+			return tm.shadowColor(u)
+		}
+		return []CSSProperty{
+			{Name: variable, Value: tintShadow(shadow, colorVariable)},
+			{Name: "box-shadow", Value: boxShadowValue},
+		}
+	})
+}
+
+// shadowColor sets the colour variable of a shadow or ring root, e.g.
+// --tw-ring-color for ring-blue-500
+func (tm *TailwindMappings) shadowColor(u *parser.ParsedUtility) []CSSProperty {
+	colors, ok := tm.utilityColor(u)
+	if !ok {
+		return nil
+	}
+	var props []CSSProperty
+	for _, color := range colors {
+		props = append(props, CSSProperty{Name: shadowColorVariables[u.Root], Value: color})
+	}
+	return props
+}
+
+// tintShadow lets a colour variable override the colours of a shadow list:
+// "0 1px 2px rgb(0 0 0 / 0.05)" becomes
+// "0 1px 2px var(--tw-shadow-color, rgb(0 0 0 / 0.05))"
+func tintShadow(shadows, colorVariable string) string {
+	parts := splitSelectorList(shadows)
+	for i, shadow := range parts {
+		fields := topLevelFields(shadow)
+		for j, field := range fields {
+			if inferDataType(field) == "color" {
+				fields[j] = "var(" + colorVariable + ", " + field + ")"
+			}
+		}
+		parts[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// topLevelFields splits a value at the spaces outside parentheses
+func topLevelFields(value string) []string {
+	var fields []string
+	depth := 0
+	start := -1
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case (c == ' ' || c == '\t' || c == '\n') && depth == 0:
+			if start >= 0 {
+				fields = append(fields, value[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, value[start:])
+	}
+	return fields
+}
@@ -0,0 +1,53 @@
+package converter
+
+import "testing"
+
+const wantBoxShadow = "box-shadow: var(--tw-inset-shadow), var(--tw-inset-ring-shadow), var(--tw-ring-offset-shadow), var(--tw-ring-shadow), var(--tw-shadow)"
+
+func TestShadowComposition(t *testing.T) {
+	tests := []struct {
+		classes    string
+		want       string
+		properties []string
+	}{
+		{
+			classes: "shadow-md",
+			want: "{ .div_1 } --tw-shadow: 0 4px 6px -1px var(--tw-shadow-color, rgb(0 0 0 / 0.1)), 0 2px 4px -2px var(--tw-shadow-color, rgb(0 0 0 / 0.1)); " +
+				wantBoxShadow,
+			properties: []string{"--tw-shadow", "--tw-shadow-color", "--tw-inset-shadow", "--tw-inset-ring-shadow", "--tw-ring-offset-shadow", "--tw-ring-shadow"},
+		},
+		{
+			classes: "shadow-lg shadow-red-500/50",
+			want: "{ .div_1 } --tw-shadow: 0 10px 15px -3px var(--tw-shadow-color, rgb(0 0 0 / 0.1)), 0 4px 6px -4px var(--tw-shadow-color, rgb(0 0 0 / 0.1)); " +
+				wantBoxShadow + "; --tw-shadow-color: color-mix(in oklab, oklch(63.7% 0.237 25.331) 50%, transparent)",
+		},
+		{
+			classes:    "ring-2 ring-blue-500",
+			want:       "{ .div_1 } --tw-ring-shadow: var(--tw-ring-inset,) 0 0 0 calc(2px + var(--tw-ring-offset-width)) var(--tw-ring-color, currentcolor); " + wantBoxShadow + "; --tw-ring-color: oklch(62.3% 0.214 259.815)",
+			properties: []string{"--tw-ring-shadow", "--tw-ring-inset", "--tw-ring-offset-width", "--tw-ring-color", "--tw-inset-shadow", "--tw-inset-ring-shadow", "--tw-ring-offset-shadow", "--tw-shadow"},
+		},
+		{
+			classes: "ring",
+			want:    "{ .div_1 } --tw-ring-shadow: var(--tw-ring-inset,) 0 0 0 calc(1px + var(--tw-ring-offset-width)) var(--tw-ring-color, currentcolor); " + wantBoxShadow,
+		},
+		{
+			classes: "inset-shadow-sm ring-1 ring-offset-2 ring-offset-white",
+			want: "{ .div_1 } --tw-ring-shadow: var(--tw-ring-inset,) 0 0 0 calc(1px + var(--tw-ring-offset-width)) var(--tw-ring-color, currentcolor); " +
+				"--tw-inset-shadow: inset 0 2px 4px var(--tw-inset-shadow-color, rgb(0 0 0 / 0.05)); " + wantBoxShadow + "; " +
+				"--tw-ring-offset-width: 2px; --tw-ring-offset-shadow: var(--tw-ring-inset,) 0 0 0 var(--tw-ring-offset-width) var(--tw-ring-offset-color); --tw-ring-offset-color: #fff",
+		},
+		{
+			classes: "ring-black/10 inset-ring-2 inset-ring-red-500",
+			want: "{ .div_1 } --tw-inset-ring-shadow: inset 0 0 0 2px var(--tw-inset-ring-color, currentcolor); " + wantBoxShadow + "; " +
+				"--tw-ring-color: color-mix(in oklab, #000 10%, transparent); --tw-inset-ring-color: oklch(63.7% 0.237 25.331)",
+		},
+		{
+			classes: "shadow-none",
+			want:    "{ .div_1 } --tw-shadow: 0 0 #0000; " + wantBoxShadow,
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, []string{tt.want}, tt.properties)
+	}
+}
@@ -0,0 +1,225 @@
+package converter
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// lengthScale describes the values a spacing or sizing utility accepts
+// besides arbitrary values: multiples of --spacing, and optionally
+// fractions, named container sizes and keywords.
+type lengthScale struct {
+	negative   bool // -mt-4
+	fractions  bool // w-1/2
+	containers bool // max-w-md, from --container-*
+	keywords   map[string]string
+}
+
+// Viewport keywords shared by the sizing utilities, e.g. h-dvh
+var viewportKeywords = map[string]string{
+	"svw": "100svw", "lvw": "100lvw", "dvw": "100dvw",
+	"svh": "100svh", "lvh": "100lvh", "dvh": "100dvh",
+}
+
+var (
+	paddingScale    = lengthScale{keywords: map[string]string{"px": "1px"}}
+	marginScale     = lengthScale{negative: true, keywords: map[string]string{"px": "1px", "auto": "auto"}}
+	insetScale      = lengthScale{negative: true, fractions: true, keywords: map[string]string{"px": "1px", "auto": "auto", "full": "100%"}}
+	translateScale  = lengthScale{negative: true, fractions: true, keywords: map[string]string{"px": "1px", "full": "100%"}}
+	translateZScale = lengthScale{negative: true, keywords: map[string]string{"px": "1px"}}
+	basisScale      = lengthScale{fractions: true, containers: true, keywords: map[string]string{"px": "1px", "auto": "auto", "full": "100%"}}
+	widthScale      = sizingScale("100vw", map[string]string{"auto": "auto"})
+	heightScale     = sizingScale("100vh", map[string]string{"auto": "auto", "lh": "1lh"})
+	sizeScale       = sizingScale("", map[string]string{"auto": "auto"})
+	minSizeScale    = sizingScale("", nil)
+	maxWidthScale   = sizingScale("100vw", map[string]string{"none": "none", "prose": "65ch"})
+	maxHeightScale  = sizingScale("100vh", map[string]string{"none": "none", "lh": "1lh"})
+)
+
+// sizingScale builds the scale of a width or height utility; screen is the
+// value of the -screen keyword, if the utility has one
+func sizingScale(screen string, extra map[string]string) lengthScale {
+	keywords := map[string]string{
+		"px": "1px", "full": "100%", "min": "min-content",
+		"max": "max-content", "fit": "fit-content",
+	}
+	for keyword, value := range viewportKeywords {
+		keywords[keyword] = value
+	}
+	if screen != "" {
+		keywords["screen"] = screen
+	}
+	for keyword, value := range extra {
+		keywords[keyword] = value
+	}
+	return lengthScale{fractions: true, containers: true, keywords: keywords}
+}
+
+func (tm *TailwindMappings) initSpacingMappings() {
+	// Padding and margin
+	tm.addLength("p", paddingScale, func(value string) []CSSProperty {
+		return []CSSProperty{{Name: "padding", Value: value}}
+	})
+	tm.addLength("m", marginScale, func(value string) []CSSProperty {
+		return []CSSProperty{{Name: "margin", Value: value}}
+	})
+	for _, direction := range []string{"x", "y", "s", "e", "t", "r", "b", "l"} {
+		direction := direction
+		tm.addLength("p"+direction, paddingScale, func(value string) []CSSProperty {
+			return tm.getPaddingProperties(direction, value)
+		})
+		tm.addLength("m"+direction, marginScale, func(value string) []CSSProperty {
+			return tm.getMarginProperties(direction, value)
+		})
+	}
+
+	// Gap
+	tm.addLengthProperties("gap", paddingScale, "gap")
+	tm.addLengthProperties("gap-x", paddingScale, "column-gap")
+	tm.addLengthProperties("gap-y", paddingScale, "row-gap")
+
+	// Inset
+	tm.addLengthProperties("inset", insetScale, "inset")
+	tm.addLengthProperties("inset-x", insetScale, "inset-inline")
+	tm.addLengthProperties("inset-y", insetScale, "inset-block")
+	tm.addLengthProperties("start", insetScale, "inset-inline-start")
+	tm.addLengthProperties("end", insetScale, "inset-inline-end")
+	for _, side := range []string{"top", "right", "bottom", "left"} {
+		tm.addLengthProperties(side, insetScale, side)
+	}
+
+	// Sizing
+	tm.addLengthProperties("w", widthScale, "width")
+	tm.addLengthProperties("min-w", minSizeScale, "min-width")
+	tm.addLengthProperties("max-w", maxWidthScale, "max-width")
+	tm.addLengthProperties("h", heightScale, "height")
+	tm.addLengthProperties("min-h", withKeyword(minSizeScale, "screen", "100vh"), "min-height")
+	tm.addLengthProperties("max-h", maxHeightScale, "max-height")
+	tm.addLengthProperties("size", sizeScale, "width", "height")
+	tm.addLengthProperties("basis", basisScale, "flex-basis")
+
+	// Translate, through the --tw-translate-* variables so the axes combine;
+	// they are registered with an initial value of 0
+	tm.staticMappings["translate-none"] = []CSSProperty{{Name: "translate", Value: "none"}}
+	tm.addLength("translate", translateScale, func(value string) []CSSProperty {
+		return []CSSProperty{
+			{Name: "--tw-translate-x", Value: value},
+			{Name: "--tw-translate-y", Value: value},
+			{Name: "translate", Value: "var(--tw-translate-x) var(--tw-translate-y)"},
+		}
+	})
+	for _, axis := range []string{"x", "y", "z"} {
+		variable := "--tw-translate-" + axis
+		scale, translate := translateScale, "var(--tw-translate-x) var(--tw-translate-y)"
+		if axis == "z" {
+			scale, translate = translateZScale, translate+" var(--tw-translate-z)"
+		}
+		tm.addLength("translate-"+axis, scale, func(value string) []CSSProperty {
+			return []CSSProperty{
+				{Name: variable, Value: value},
+				{Name: "translate", Value: translate},
+			}
+		})
+	}
+}
+
+func withKeyword(scale lengthScale, keyword, value string) lengthScale {
+	keywords := make(map[string]string, len(scale.keywords)+1)
+	for k, v := range scale.keywords {
+		keywords[k] = v
+	}
+	keywords[keyword] = value
+	scale.keywords = keywords
+	return scale
+}
+
+// addLength registers a utility whose value resolves along a length scale
+func (tm *TailwindMappings) addLength(root string, scale lengthScale, declarations func(value string) []CSSProperty) {
+	tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+		if value := tm.resolveLength(u, scale); value != "" {
+			return declarations(value)
+		}
+		return nil
+	})
+}
+
+// addLengthProperties registers a length utility setting the given properties
+func (tm *TailwindMappings) addLengthProperties(root string, scale lengthScale, properties ...string) {
+	tm.addLength(root, scale, func(value string) []CSSProperty {
+		props := make([]CSSProperty, 0, len(properties))
+		for _, property := range properties {
+			props = append(props, CSSProperty{Name: property, Value: value})
+		}
+		return props
+	})
+}
+
+// resolveLength returns the CSS value of a spacing or sizing utility, or ""
+// when the value is not on its scale
+func (tm *TailwindMappings) resolveLength(u *parser.ParsedUtility, scale lengthScale) string {
+	if u.Value == nil || (u.Negative && !scale.negative) {
+		return ""
+	}
+
+	value := ""
+	switch {
+	case u.Value.Kind == parser.ArbitraryValue:
+		if u.Modifier == nil {
+			value = u.Value.Text
+		}
+	case u.Value.Fraction != "":
+		if scale.fractions {
+			value = fractionPercent(u.Value.Fraction)
+		}
+	case u.Modifier != nil:
+	case scale.keywords[u.Value.Text] != "":
+		value = scale.keywords[u.Value.Text]
+	default:
+		if token, ok := tm.theme.Value("--spacing-" + u.Value.Text); ok {
+			value = token
+		} else if numberPattern.MatchString(u.Value.Text) {
+			value = tm.convertSpacing(u.Value.Text)
+		} else if scale.containers {
+			value, _ = tm.theme.Value("--container-" + u.Value.Text)
+		}
+	}
+
+	if value == "" || !u.Negative {
+		return value
+	}
+	return negateLength(value)
+}
+
+// fractionPercent turns a fraction like 1/2 into a percentage, or "" if it
+// is not a fraction of whole numbers
+func fractionPercent(fraction string) string {
+	slash := strings.IndexByte(fraction, '/')
+	if slash < 0 {
+		return ""
+	}
+	numerator, errN := strconv.Atoi(fraction[:slash])
+	denominator, errD := strconv.Atoi(fraction[slash+1:])
+	if errN != nil || errD != nil || denominator == 0 {
+		return ""
+	}
+	percent := math.Round(float64(numerator)/float64(denominator)*100*1e4) / 1e4
+	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
+
+// negateLength returns the negative of a length: -1rem for 1rem, and a
+// calc() for anything that is not a plain dimension
+func negateLength(value string) string {
+	switch {
+	case value == "0":
+		return value
+	case value == "auto":
+		return ""
+	case strings.HasPrefix(value, "-") && dimensionPattern.MatchString(value):
+		return value[1:]
+	case dimensionPattern.MatchString(value):
+		return "-" + value
+	}
+	return "calc(" + value + " * -1)"
+}
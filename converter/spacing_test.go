@@ -0,0 +1,43 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpacingAndSizing(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string // Declarations joined by "; ", empty when the class does not convert
+	}{
+		{"p-0.5", "padding: 0.125rem"},
+		{"mt-2.5", "margin-top: 0.625rem"},
+		{"-mt-4", "margin-top: -1rem"},
+		{"-m-px", "margin: -1px"},
+		{"m-auto", "margin: auto"},
+		{"-m-auto", ""},
+		{"-p-4", ""},
+		{"-mx-[10px]", "margin-left: -10px; margin-right: -10px"},
+		{"-mt-(--x)", "margin-top: calc(var(--x) * -1)"},
+		{"-inset-2", "inset: -0.5rem"},
+		{"-top-1/2", "top: -50%"},
+		{"-translate-x-1/2", "--tw-translate-x: -50%; translate: var(--tw-translate-x) var(--tw-translate-y)"},
+		{"w-1/2", "width: 50%"},
+		{"size-1/3", "width: 33.3333%; height: 33.3333%"},
+		{"basis-1/2", "flex-basis: 50%"},
+		{"w-full", "width: 100%"},
+		{"w-screen", "width: 100vw"},
+		{"h-dvh", "height: 100dvh"},
+		{"min-h-svh", "min-height: 100svh"},
+		{"w-fit", "width: fit-content"},
+		{"w-px", "width: 1px"},
+		{"max-w-md", "max-width: 28rem"},
+	}
+
+	tm := NewTailwindMappings(DefaultOptions())
+	for _, tt := range tests {
+		if got := strings.Join(declarations(t, tm, tt.class), "; "); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.class, got, tt.want)
+		}
+	}
+}
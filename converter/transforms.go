@@ -0,0 +1,148 @@
+package converter
+
+import (
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// transformValue composes the 3D rotations and skews. Each utility sets its
+// own --tw-* variable, so rotate-x-12 on an element and skew-y-3 on hover
+// end up in the same transform.
+const transformValue = "var(--tw-rotate-x,) var(--tw-rotate-y,) var(--tw-rotate-z,) var(--tw-skew-x,) var(--tw-skew-y,)"
+
+// Keywords of origin-* and perspective-origin-*
+var originKeywords = map[string]string{
+	"center": "center", "top": "top", "top-right": "100% 0",
+	"right": "100%", "bottom-right": "100% 100%", "bottom": "bottom",
+	"bottom-left": "0 100%", "left": "0", "top-left": "0 0",
+}
+
+func (tm *TailwindMappings) initTransformMappings() {
+	// Scale, scale-95 and -scale-x-100, through --tw-scale-* so the axes
+	// combine
+	tm.staticMappings["scale-none"] = []CSSProperty{{Name: "scale", Value: "none"}}
+	tm.addDynamic("scale", func(u *parser.ParsedUtility) []CSSProperty {
+		value := scaleValue(u)
+		if value == "" {
+			return nil
+		}
+		return []CSSProperty{
+			{Name: "--tw-scale-x", Value: value},
+			{Name: "--tw-scale-y", Value: value},
+			{Name: "--tw-scale-z", Value: value},
+			{Name: "scale", Value: "var(--tw-scale-x) var(--tw-scale-y)"},
+		}
+	})
+	for _, axis := range []string{"x", "y", "z"} {
+		variable := "--tw-scale-" + axis
+		scale := "var(--tw-scale-x) var(--tw-scale-y)"
+		if axis == "z" {
+			scale += " var(--tw-scale-z)"
+		}
+		tm.addDynamic("scale-"+axis, func(u *parser.ParsedUtility) []CSSProperty {
+			if value := scaleValue(u); value != "" {
+				return []CSSProperty{{Name: variable, Value: value}, {Name: "scale", Value: scale}}
+			}
+			return nil
+		})
+	}
+
+	// Rotation in the plane uses the rotate property; around the X, Y and Z
+	// axes and skews go through transform
+	tm.staticMappings["rotate-none"] = []CSSProperty{{Name: "rotate", Value: "none"}}
+	tm.addDynamic("rotate", func(u *parser.ParsedUtility) []CSSProperty {
+		if angle := angleValue(u); angle != "" {
+			return []CSSProperty{{Name: "rotate", Value: angle}}
+		}
+		return nil
+	})
+	for _, axis := range []string{"x", "y", "z"} {
+		tm.addTransformFunction("rotate-"+axis, "--tw-rotate-"+axis, "rotate"+strings.ToUpper(axis))
+	}
+	tm.addDynamic("skew", func(u *parser.ParsedUtility) []CSSProperty {
+		angle := angleValue(u)
+		if angle == "" {
+			return nil
+		}
+		return []CSSProperty{
+			{Name: "--tw-skew-x", Value: "skewX(" + angle + ")"},
+			{Name: "--tw-skew-y", Value: "skewY(" + angle + ")"},
+			{Name: "transform", Value: transformValue},
+		}
+	})
+	tm.addTransformFunction("skew-x", "--tw-skew-x", "skewX")
+	tm.addTransformFunction("skew-y", "--tw-skew-y", "skewY")
+
+	// Transform keywords
+	tm.staticMappings["transform"] = []CSSProperty{{Name: "transform", Value: transformValue}}
+	tm.staticMappings["transform-cpu"] = []CSSProperty{{Name: "transform", Value: transformValue}}
+	tm.staticMappings["transform-gpu"] = []CSSProperty{{Name: "transform", Value: "translateZ(0) " + transformValue}}
+	tm.staticMappings["transform-none"] = []CSSProperty{{Name: "transform", Value: "none"}}
+	tm.staticMappings["transform-3d"] = []CSSProperty{{Name: "transform-style", Value: "preserve-3d"}}
+	tm.staticMappings["transform-flat"] = []CSSProperty{{Name: "transform-style", Value: "flat"}}
+	tm.staticMappings["backface-visible"] = []CSSProperty{{Name: "backface-visibility", Value: "visible"}}
+	tm.staticMappings["backface-hidden"] = []CSSProperty{{Name: "backface-visibility", Value: "hidden"}}
+
+	// Origins and perspective
+	for keyword, position := range originKeywords {
+		tm.staticMappings["origin-"+keyword] = []CSSProperty{{Name: "transform-origin", Value: position}}
+		tm.staticMappings["perspective-origin-"+keyword] = []CSSProperty{{Name: "perspective-origin", Value: position}}
+	}
+	tm.staticMappings["perspective-none"] = []CSSProperty{{Name: "perspective", Value: "none"}}
+	tm.addDynamic("perspective", func(u *parser.ParsedUtility) []CSSProperty {
+		if perspective, ok := tm.themeValue("perspective", u); ok {
+			return []CSSProperty{{Name: "perspective", Value: perspective}}
+		}
+		return nil
+	})
+}
+
+// addTransformFunction registers a utility setting one function of the
+// composed transform, e.g. rotate-x-45 setting --tw-rotate-x to rotateX(45deg)
+func (tm *TailwindMappings) addTransformFunction(root, variable, function string) {
+	tm.addDynamic(root, func(u *parser.ParsedUtility) []CSSProperty {
+		angle := angleValue(u)
+		if angle == "" {
+			return nil
+		}
+		return []CSSProperty{
+			{Name: variable, Value: function + "(" + angle + ")"},
+			{Name: "transform", Value: transformValue},
+		}
+	})
+}
+
+// scaleValue returns the factor of a scale utility: 95% for scale-95, or an
+// arbitrary value
+func scaleValue(u *parser.ParsedUtility) string {
+	return signedValue(u, "%")
+}
+
+// angleValue returns the angle of a rotate or skew utility: 45deg for
+// rotate-45, or an arbitrary value
+func angleValue(u *parser.ParsedUtility) string {
+	return signedValue(u, "deg")
+}
+
+// signedValue returns a number with the given unit, or an arbitrary value,
+// negated for a negative utility
+func signedValue(u *parser.ParsedUtility, unit string) string {
+	if u.Value == nil || u.Modifier != nil {
+		return ""
+	}
+
+	value := ""
+	switch {
+	case u.Value.Kind == parser.ArbitraryValue:
+		value = u.Value.Text
+	case numberPattern.MatchString(u.Value.Text):
+		value = u.Value.Text + unit
+	default:
+		return ""
+	}
+
+	if u.Negative {
+		return negateLength(value)
+	}
+	return value
+}
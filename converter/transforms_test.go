@@ -0,0 +1,40 @@
+package converter
+
+import "testing"
+
+func TestTransformComposition(t *testing.T) {
+	tests := []struct {
+		classes    string
+		want       []string
+		properties []string
+	}{
+		{
+			classes: "translate-x-2 rotate-45 scale-95",
+			want: []string{
+				"{ .div_1 } --tw-translate-x: 0.5rem; translate: var(--tw-translate-x) var(--tw-translate-y); " +
+					"--tw-scale-x: 95%; --tw-scale-y: 95%; --tw-scale-z: 95%; scale: var(--tw-scale-x) var(--tw-scale-y); rotate: 45deg",
+			},
+			properties: []string{"--tw-translate-x", "--tw-translate-y", "--tw-scale-x", "--tw-scale-y", "--tw-scale-z"},
+		},
+		{
+			classes: "translate-x-2 hover:translate-y-4 hover:rotate-12",
+			want: []string{
+				"{ .div_1 } --tw-translate-x: 0.5rem; translate: var(--tw-translate-x) var(--tw-translate-y)",
+				"@media (hover: hover) { .div_1:hover } --tw-translate-y: 1rem; translate: var(--tw-translate-x) var(--tw-translate-y); rotate: 12deg",
+			},
+			properties: []string{"--tw-translate-x", "--tw-translate-y"},
+		},
+		{
+			classes: "skew-x-6 -rotate-3 scale-x-50",
+			want: []string{
+				"{ .div_1 } --tw-scale-x: 50%; scale: var(--tw-scale-x) var(--tw-scale-y); rotate: -3deg; " +
+					"--tw-skew-x: skewX(6deg); transform: var(--tw-rotate-x,) var(--tw-rotate-y,) var(--tw-rotate-z,) var(--tw-skew-x,) var(--tw-skew-y,)",
+			},
+			properties: []string{"--tw-scale-x", "--tw-scale-y", "--tw-skew-x", "--tw-rotate-x", "--tw-rotate-y", "--tw-rotate-z", "--tw-skew-y"},
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, tt.want, tt.properties)
+	}
+}
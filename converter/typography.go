@@ -0,0 +1,158 @@
+package converter
+
+import "tailwind-v4-to-css-converter/internal/parser"
+
+// numericVariantValue composes the font-variant-numeric utilities, so
+// tabular-nums and slashed-zero on one element both apply
+const numericVariantValue = "var(--tw-ordinal,) var(--tw-slashed-zero,) var(--tw-numeric-figure,) var(--tw-numeric-spacing,) var(--tw-numeric-fraction,)"
+
+// Variables set by each font-variant-numeric utility
+var numericVariants = map[string]string{
+	"ordinal":            "--tw-ordinal",
+	"slashed-zero":       "--tw-slashed-zero",
+	"lining-nums":        "--tw-numeric-figure",
+	"oldstyle-nums":      "--tw-numeric-figure",
+	"proportional-nums":  "--tw-numeric-spacing",
+	"tabular-nums":       "--tw-numeric-spacing",
+	"diagonal-fractions": "--tw-numeric-fraction",
+	"stacked-fractions":  "--tw-numeric-fraction",
+}
+
+var indentScale = lengthScale{negative: true, keywords: map[string]string{"px": "1px"}}
+
+func (tm *TailwindMappings) initTypographyMappings() {
+	// Line height, leading-tight from the theme or leading-6 on the spacing
+	// scale; --tw-leading keeps it over the line height of text-<size>
+	tm.addDynamic("leading", func(u *parser.ParsedUtility) []CSSProperty {
+		leading := ""
+		switch {
+		case namedValue(u) == "none":
+			leading = "1"
+		case u.Value != nil && u.Modifier == nil && !u.Negative:
+			leading = tm.lineHeight(u.Value)
+		}
+		if leading == "" {
+			return nil
+		}
+		return []CSSProperty{
+			{Name: "--tw-leading", Value: leading},
+			{Name: "line-height", Value: leading},
+		}
+	})
+
+	// Letter spacing, tracking-wide and -tracking-wider
+	tm.addDynamic("tracking", func(u *parser.ParsedUtility) []CSSProperty {
+		unsigned := *u
+		unsigned.Negative = false
+		tracking, _ := tm.themeValue("tracking", &unsigned)
+		if tracking == "" {
+			tracking = arbitraryValue(&unsigned)
+		}
+		if tracking == "" {
+			return nil
+		}
+		if u.Negative {
+			tracking = "calc(" + tracking + " * -1)"
+		}
+		return []CSSProperty{
+			{Name: "--tw-tracking", Value: tracking},
+			{Name: "letter-spacing", Value: tracking},
+		}
+	})
+
+	// Font style, smoothing and stretch
+	tm.staticMappings["italic"] = []CSSProperty{{Name: "font-style", Value: "italic"}}
+	tm.staticMappings["not-italic"] = []CSSProperty{{Name: "font-style", Value: "normal"}}
+	tm.staticMappings["antialiased"] = []CSSProperty{
+		{Name: "-webkit-font-smoothing", Value: "antialiased"},
+		{Name: "-moz-osx-font-smoothing", Value: "grayscale"},
+	}
+	tm.staticMappings["subpixel-antialiased"] = []CSSProperty{
+		{Name: "-webkit-font-smoothing", Value: "auto"},
+		{Name: "-moz-osx-font-smoothing", Value: "auto"},
+	}
+	tm.addKeywords("font-stretch", "font-stretch", "ultra-condensed", "extra-condensed",
+		"condensed", "semi-condensed", "normal", "semi-expanded", "expanded",
+		"extra-expanded", "ultra-expanded")
+	tm.addDynamic("font-stretch", func(u *parser.ParsedUtility) []CSSProperty {
+		stretch := arbitraryValue(u)
+		if value := namedValue(u); percentagePattern.MatchString(value) {
+			stretch = value
+		}
+		if stretch == "" {
+			return nil
+		}
+		return []CSSProperty{{Name: "font-stretch", Value: stretch}}
+	})
+
+	// Numeric variants, tabular-nums and friends
+	tm.staticMappings["normal-nums"] = []CSSProperty{{Name: "font-variant-numeric", Value: "normal"}}
+	for class, variable := range numericVariants {
+		tm.staticMappings[class] = []CSSProperty{
+			{Name: variable, Value: class},
+			{Name: "font-variant-numeric", Value: numericVariantValue},
+		}
+	}
+
+	// Text decoration, underline, decoration-wavy, decoration-2 and
+	// underline-offset-4; decoration colours are set with the other colours
+	for class, line := range map[string]string{
+		"underline": "underline", "overline": "overline",
+		"line-through": "line-through", "no-underline": "none",
+	} {
+		tm.staticMappings[class] = []CSSProperty{{Name: "text-decoration-line", Value: line}}
+	}
+	tm.addKeywords("decoration", "text-decoration-style", "solid", "double", "dotted", "dashed", "wavy")
+	tm.addKeywords("decoration", "text-decoration-thickness", "auto", "from-font")
+	tm.addDynamic("decoration", func(u *parser.ParsedUtility) []CSSProperty {
+		if thickness := numericValue(u); thickness != "" {
+			return []CSSProperty{{Name: "text-decoration-thickness", Value: thickness + "px"}}
+		}
+		return nil
+	})
+	tm.staticMappings["underline-offset-auto"] = []CSSProperty{{Name: "text-underline-offset", Value: "auto"}}
+	tm.addDynamic("underline-offset", func(u *parser.ParsedUtility) []CSSProperty {
+		if u.Value == nil || u.Value.Kind != parser.NamedValue || !numberPattern.MatchString(u.Value.Text) {
+			return nil
+		}
+		offset := u.Value.Text + "px"
+		if u.Negative {
+			offset = negateLength(offset)
+		}
+		return []CSSProperty{{Name: "text-underline-offset", Value: offset}}
+	})
+
+	// Text transform, whitespace and wrapping
+	tm.staticMappings["uppercase"] = []CSSProperty{{Name: "text-transform", Value: "uppercase"}}
+	tm.staticMappings["lowercase"] = []CSSProperty{{Name: "text-transform", Value: "lowercase"}}
+	tm.staticMappings["capitalize"] = []CSSProperty{{Name: "text-transform", Value: "capitalize"}}
+	tm.staticMappings["normal-case"] = []CSSProperty{{Name: "text-transform", Value: "none"}}
+	tm.addKeywords("whitespace", "white-space", "normal", "nowrap", "pre", "pre-line", "pre-wrap", "break-spaces")
+	tm.addKeywords("text", "text-wrap", "wrap", "nowrap", "balance", "pretty")
+	tm.addKeywords("hyphens", "hyphens", "none", "manual", "auto")
+
+	// Indentation and vertical alignment, indent-8 and align-middle
+	tm.addLengthProperties("indent", indentScale, "text-indent")
+	tm.addKeywords("align", "vertical-align", "baseline", "top", "middle", "bottom",
+		"text-top", "text-bottom", "sub", "super")
+
+	// Lists, list-disc and list-inside
+	tm.addKeywords("list", "list-style-position", "inside", "outside")
+	tm.addKeywords("list", "list-style-type", "disc", "decimal", "none")
+	tm.staticMappings["list-image-none"] = []CSSProperty{{Name: "list-style-image", Value: "none"}}
+}
+
+// lineHeight resolves the value of leading-* or the modifier of text-lg/7:
+// a theme --leading-* token, a multiple of --spacing, or a bracketed value
+func (tm *TailwindMappings) lineHeight(value *parser.UtilityValue) string {
+	if value.Kind == parser.ArbitraryValue {
+		return value.Text
+	}
+	if leading, ok := tm.theme.Value("--leading-" + value.Text); ok {
+		return leading
+	}
+	if numberPattern.MatchString(value.Text) {
+		return tm.convertSpacing(value.Text)
+	}
+	return ""
+}
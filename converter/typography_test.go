@@ -0,0 +1,26 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTracking(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string
+	}{
+		{"tracking-wide", "0.025em"},
+		{"-tracking-wide", "calc(0.025em * -1)"},
+		{"tracking-[0.2em]", "0.2em"},
+		{"-tracking-[0.2em]", "calc(0.2em * -1)"},
+	}
+
+	tm := NewTailwindMappings(DefaultOptions())
+	for _, tt := range tests {
+		want := "--tw-tracking: " + tt.want + "\nletter-spacing: " + tt.want
+		if got := strings.Join(declarations(t, tm, tt.class), "\n"); got != want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.class, got, want)
+		}
+	}
+}
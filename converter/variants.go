@@ -0,0 +1,791 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+// VariantEngine turns a class's variant stack, e.g. md:hover:focus-visible,
+// into the selector and at-rules wrapping the utility's declarations.
+// Variants apply left to right, the way Tailwind v4 reads them.
+type VariantEngine struct {
+	staticVariants     map[string]*variantHandler
+	functionalVariants []*functionalVariant
+	compoundVariants   []*compoundVariant
+	breakpoints        []breakpoint
+	containers         []breakpoint
+	nextOrder          int
+}
+
+// RuleScope is where a utility's declarations end up: a selector in which
+// "&" stands for the element's semantic class, nested in at-rules.
+type RuleScope struct {
+	Selector     string
+	AtRules      []string      // Outermost first
+	Order        []int         // Registration order of the applied variants, for sorting
+	Declarations []CSSProperty // Declarations the variants imply, like content for before:
+}
+
+type variantHandler struct {
+	order int
+	apply func(scope *RuleScope)
+}
+
+// functionalVariant handles a family of variants sharing a prefix, like
+// supports-grid and supports-[display:grid]. The value is the text after
+// the prefix, or the arbitrary value.
+type functionalVariant struct {
+	prefix string
+	order  int
+	apply  func(scope *RuleScope, value string, v parser.Variant) bool
+}
+
+// compoundVariant wraps another variant, like group-hover or peer-checked.
+// The inner variant is applied to a scope of its own first; for an arbitrary
+// value, as in group-[.is-published], the inner scope has no selector.
+type compoundVariant struct {
+	prefix string
+	order  int
+	apply  func(scope *RuleScope, inner RuleScope, v parser.Variant, markers Markers) bool
+}
+
+// Markers are the selectors of the elements a class's group-* and peer-*
+// variants refer to, keyed by group name, "" for a plain group or peer
+type Markers struct {
+	Groups map[string]string // Nearest ancestor marked group or group/<name>
+	Peers  map[string]string // Nearest preceding sibling marked peer or peer/<name>
+}
+
+// missingMarkerError reports a group-* or peer-* variant without a marked
+// ancestor or sibling to refer to
+type missingMarkerError struct {
+	marker  string
+	variant string
+}
+
+func (e *missingMarkerError) Error() string {
+	return fmt.Sprintf("no element marked %q for %q", e.marker, e.variant)
+}
+
+type breakpoint struct {
+	name  string
+	width string
+}
+
+// DarkModeMedia makes dark: follow the operating system's colour scheme
+// through prefers-color-scheme, as Tailwind v4 does by default
+const DarkModeMedia = "media"
+
+// ParseDarkMode validates a dark mode strategy given on the command line:
+// "media", "class" for a .dark ancestor, or a selector in which "&" stands
+// for the element, e.g. "[data-theme=dark] &". A selector without "&" is
+// taken as an ancestor.
+func ParseDarkMode(mode string) (string, error) {
+	mode = strings.TrimSpace(mode)
+	switch {
+	case mode == "" || mode == DarkModeMedia:
+		return DarkModeMedia, nil
+	case mode == "class":
+		return ".dark &", nil
+	case strings.ContainsAny(mode, "{};"):
+		return "", fmt.Errorf("invalid dark mode selector %q", mode)
+	case !strings.Contains(mode, "&"):
+		return mode + " &", nil
+	}
+	return mode, nil
+}
+
+func NewVariantEngine(options Options) *VariantEngine {
+	ve := &VariantEngine{
+		staticVariants: make(map[string]*variantHandler),
+		breakpoints:    themeWidths(options.Theme, "breakpoint"),
+		containers:     themeWidths(options.Theme, "container"),
+	}
+
+	ve.initVariants(options.DarkMode)
+	ve.initCustomVariants(options.Theme.CustomVariants())
+	return ve
+}
+
+// themeWidths returns the theme's --breakpoint-* or --container-* tokens,
+// narrowest first
+func themeWidths(theme *parser.Theme, namespace string) []breakpoint {
+	var breakpoints []breakpoint
+	for _, token := range theme.Namespace(namespace) {
+		breakpoints = append(breakpoints, breakpoint{name: token.Name, width: token.Value})
+	}
+
+	sort.SliceStable(breakpoints, func(i, j int) bool {
+		a, aOK := lengthInPixels(breakpoints[i].width)
+		b, bOK := lengthInPixels(breakpoints[j].width)
+		return aOK && bOK && a < b
+	})
+	return breakpoints
+}
+
+// lengthInPixels converts a px, rem or em length for comparison
+func lengthInPixels(length string) (float64, bool) {
+	match := dimensionPattern.FindStringSubmatch(length)
+	if match == nil {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	switch match[2] {
+	case "px":
+		return value, true
+	case "rem", "em":
+		return value * 16, true
+	}
+	return 0, false
+}
+
+// Apply works out the scope of a utility with the given variant stack
+func (ve *VariantEngine) Apply(variants []parser.Variant, markers Markers) (RuleScope, error) {
+	scope := RuleScope{Selector: "&"}
+
+	for _, variant := range variants {
+		order, ok := ve.apply(&scope, variant, markers)
+		if !ok {
+			if marker := markerName(variant); marker != "" && !markers.has(marker, variant.Modifier) {
+				return scope, &missingMarkerError{marker: marker, variant: variant.Raw}
+			}
+			return scope, fmt.Errorf("unknown variant %q", variant.Raw)
+		}
+		scope.Order = append(scope.Order, order)
+	}
+
+	return scope, nil
+}
+
+// markerName returns the marker class a group-* or peer-* variant needs,
+// e.g. "group/card" for group-hover/card
+func markerName(variant parser.Variant) string {
+	for _, kind := range []string{"group", "peer"} {
+		if variant.Name == kind || strings.HasPrefix(variant.Name, kind+"-") {
+			if variant.Modifier != "" {
+				return kind + "/" + variant.Modifier
+			}
+			return kind
+		}
+	}
+	return ""
+}
+
+func (m Markers) has(marker, name string) bool {
+	if strings.HasPrefix(marker, "peer") {
+		_, exists := m.Peers[name]
+		return exists
+	}
+	_, exists := m.Groups[name]
+	return exists
+}
+
+func (ve *VariantEngine) apply(scope *RuleScope, variant parser.Variant, markers Markers) (int, bool) {
+	if variant.Value == nil && variant.Modifier == "" {
+		if handler, exists := ve.staticVariants[variant.Name]; exists {
+			handler.apply(scope)
+			return handler.order, true
+		}
+	}
+
+	for _, functional := range ve.functionalVariants {
+		var value string
+		switch {
+		case variant.Name == functional.prefix && variant.Value != nil:
+			value = variant.Value.Text
+		case variant.Name == functional.prefix:
+			// Only a modifier, as in @lg/sidebar
+		case strings.HasPrefix(variant.Name, functional.prefix+"-") && variant.Value == nil:
+			value = strings.TrimPrefix(variant.Name, functional.prefix+"-")
+		default:
+			continue
+		}
+
+		if functional.apply(scope, value, variant) {
+			return functional.order, true
+		}
+	}
+
+	for _, compound := range ve.compoundVariants {
+		inner := RuleScope{Selector: "&"}
+		switch {
+		case variant.Name == compound.prefix && variant.Value != nil:
+			// Arbitrary value, group-[.is-published] or has-[img], left to
+			// the compound variant
+			inner.Selector = ""
+		case strings.HasPrefix(variant.Name, compound.prefix+"-"):
+			name := strings.TrimPrefix(variant.Name, compound.prefix+"-")
+			innerVariant := parser.Variant{Raw: name, Name: name, Value: variant.Value}
+			if _, ok := ve.apply(&inner, innerVariant, markers); !ok {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if compound.apply(scope, inner, variant, markers) {
+			return compound.order, true
+		}
+	}
+
+	return 0, false
+}
+
+func (ve *VariantEngine) initVariants(darkMode string) {
+	// Children and descendants, *:rounded and **:p-2
+	ve.addSelectorVariant("*", ":is(& > *)")
+	ve.addSelectorVariant("**", ":is(& *)")
+
+	// not-first, not-hover, not-supports-grid and not-[.active]
+	ve.addCompoundVariant("not", func(scope *RuleScope, inner RuleScope, v parser.Variant, markers Markers) bool {
+		if inner.Selector == "" {
+			scope.selector("&:not(" + relativeSelector(arbitrarySelector(v.Value.Text)) + ")")
+			return true
+		}
+		if inner.Selector == "&" {
+			// Only at-rules, negated one by one
+			for _, atRule := range inner.AtRules {
+				negated, ok := negateAtRule(atRule)
+				if !ok {
+					return false
+				}
+				scope.wrap(negated)
+			}
+			return len(inner.AtRules) > 0
+		}
+		// The at-rules of a selector variant, like hover's @media (hover:
+		// hover), only limit where it can match, so they are dropped
+		scope.selector("&:not(" + relativeSelector(inner.Selector) + ")")
+		return true
+	})
+
+	// group-*, relative to the marked ancestor or sibling:
+	// group-hover becomes ".card:hover &", peer-checked ".input:checked ~ &"
+	ve.addMarkerVariant("group", func(markers Markers) map[string]string { return markers.Groups }, " ")
+	ve.addMarkerVariant("peer", func(markers Markers) map[string]string { return markers.Peers }, " ~ ")
+
+	// Pseudo-elements. marker: and selection: also style descendants, as
+	// list items and text inherit them; before: and after: get the content
+	// set by content-* utilities, "" unless one is used.
+	ve.addSelectorVariant("first-letter", "&::first-letter")
+	ve.addSelectorVariant("first-line", "&::first-line")
+	ve.addSelectorVariant("marker", "& *::marker, &::marker")
+	ve.addSelectorVariant("selection", "& *::selection, &::selection")
+	ve.addSelectorVariant("file", "&::file-selector-button")
+	ve.addSelectorVariant("placeholder", "&::placeholder")
+	ve.addSelectorVariant("backdrop", "&::backdrop")
+	for _, name := range []string{"before", "after"} {
+		template := "&::" + name
+		ve.addStaticVariant(name, func(scope *RuleScope) {
+			scope.selector(template)
+			scope.Declarations = append(scope.Declarations, CSSProperty{Name: "content", Value: "var(--tw-content)"})
+		})
+	}
+
+	// Structural pseudo-classes and pseudo-classes, in the order Tailwind
+	// sorts them
+	ve.addSelectorVariant("first", "&:first-child")
+	ve.addSelectorVariant("last", "&:last-child")
+	ve.addSelectorVariant("only", "&:only-child")
+	ve.addSelectorVariant("odd", "&:nth-child(odd)")
+	ve.addSelectorVariant("even", "&:nth-child(even)")
+	pseudoClasses := []string{
+		"first-of-type", "last-of-type", "only-of-type", "visited", "target", "default", "checked", "indeterminate",
+		"placeholder-shown", "autofill", "optional", "required", "valid",
+		"invalid", "user-valid", "user-invalid", "in-range", "out-of-range",
+		"read-only", "empty", "focus-within",
+	}
+	for _, name := range pseudoClasses {
+		ve.addSelectorVariant(name, "&:"+name)
+	}
+	ve.addSelectorVariant("open", "&:is([open], :popover-open, :open)")
+
+	// hover only applies on devices that can hover, as in Tailwind v4
+	ve.addStaticVariant("hover", func(scope *RuleScope) {
+		scope.wrap("@media (hover: hover)")
+		scope.selector("&:hover")
+	})
+
+	for _, name := range []string{"focus", "focus-visible", "active", "enabled", "disabled"} {
+		ve.addSelectorVariant(name, "&:"+name)
+	}
+	ve.addSelectorVariant("inert", "&:is([inert], [inert] *)")
+
+	// in-focus and in-[.dark], matching elements inside a match, and
+	// has-[img] and has-checked, matching elements containing a match
+	ve.addRelationalVariant("in", func(target string) string { return ":where(" + target + ") &" })
+	ve.addRelationalVariant("has", func(target string) string { return "&:has(" + target + ")" })
+
+	// aria-checked and aria-[sort=ascending]
+	ve.addFunctionalVariant("aria", func(scope *RuleScope, value string, v parser.Variant) bool {
+		switch {
+		case v.Value != nil:
+			scope.selector("&[aria-" + value + "]")
+		case ariaStates[value]:
+			scope.selector(`&[aria-` + value + `="true"]`)
+		default:
+			return false
+		}
+		return true
+	})
+
+	// data-active and data-[state=open]
+	ve.addFunctionalVariant("data", func(scope *RuleScope, value string, v parser.Variant) bool {
+		if value == "" {
+			return false
+		}
+		scope.selector("&[data-" + value + "]")
+		return true
+	})
+
+	// nth-3, nth-last-[2n+1], nth-of-type-2 and nth-last-of-type-[odd]
+	for _, nth := range []string{"nth", "nth-last", "nth-of-type", "nth-last-of-type"} {
+		pseudoClass := ":" + nth
+		if !strings.HasSuffix(nth, "of-type") {
+			pseudoClass += "-child"
+		}
+		ve.addFunctionalVariant(nth, func(scope *RuleScope, value string, v parser.Variant) bool {
+			if v.Value != nil {
+				value = globalSelector(value)
+			} else if !numberPattern.MatchString(value) {
+				return false
+			}
+			scope.selector("&" + pseudoClass + "(" + value + ")")
+			return true
+		})
+	}
+
+	// Feature queries, supports-grid and supports-[display:grid]
+	ve.addFunctionalVariant("supports", func(scope *RuleScope, value string, v parser.Variant) bool {
+		if value == "" {
+			return false
+		}
+		if strings.HasPrefix(value, "not ") || strings.HasPrefix(value, "selector(") || strings.HasPrefix(value, "font-") {
+			scope.wrap("@supports " + value)
+		} else if strings.Contains(value, ":") {
+			scope.wrap("@supports (" + value + ")")
+		} else {
+			scope.wrap("@supports (" + value + ": var(--tw))")
+		}
+		return true
+	})
+
+	// User preference media queries
+	ve.addAtRuleVariant("motion-safe", "@media (prefers-reduced-motion: no-preference)")
+	ve.addAtRuleVariant("motion-reduce", "@media (prefers-reduced-motion: reduce)")
+	ve.addAtRuleVariant("contrast-more", "@media (prefers-contrast: more)")
+	ve.addAtRuleVariant("contrast-less", "@media (prefers-contrast: less)")
+
+	// Breakpoints: max-* first, widest first, then sm, md, ... ascending
+	for i := len(ve.breakpoints) - 1; i >= 0; i-- {
+		ve.addAtRuleVariant("max-"+ve.breakpoints[i].name, "@media (width < "+ve.breakpoints[i].width+")")
+	}
+	ve.addFunctionalVariant("max", func(scope *RuleScope, value string, v parser.Variant) bool {
+		if v.Value == nil {
+			return false
+		}
+		scope.wrap("@media (width < " + value + ")")
+		return true
+	})
+	for _, bp := range ve.breakpoints {
+		ve.addAtRuleVariant(bp.name, "@media (width >= "+bp.width+")")
+	}
+	ve.addFunctionalVariant("min", func(scope *RuleScope, value string, v parser.Variant) bool {
+		if v.Value == nil {
+			return false
+		}
+		scope.wrap("@media (width >= " + value + ")")
+		return true
+	})
+
+	// Container queries: @max-md, @md and @min-[400px], against the nearest
+	// container or a named one, @lg/sidebar
+	for i := len(ve.containers) - 1; i >= 0; i-- {
+		ve.addContainerVariant("@max-"+ve.containers[i].name, "width < "+ve.containers[i].width)
+	}
+	ve.addFunctionalVariant("@max", func(scope *RuleScope, value string, v parser.Variant) bool {
+		if v.Value == nil {
+			return false
+		}
+		scope.wrap(containerQuery(v.Modifier, "width < "+value))
+		return true
+	})
+	for _, container := range ve.containers {
+		ve.addContainerVariant("@"+container.name, "width >= "+container.width)
+	}
+	for _, prefix := range []string{"@", "@min"} {
+		ve.addFunctionalVariant(prefix, func(scope *RuleScope, value string, v parser.Variant) bool {
+			if v.Value == nil {
+				return false
+			}
+			scope.wrap(containerQuery(v.Modifier, "width >= "+value))
+			return true
+		})
+	}
+
+	ve.addAtRuleVariant("portrait", "@media (orientation: portrait)")
+	ve.addAtRuleVariant("landscape", "@media (orientation: landscape)")
+	ve.addSelectorVariant("ltr", `&:where(:dir(ltr), [dir="ltr"], [dir="ltr"] *)`)
+	ve.addSelectorVariant("rtl", `&:where(:dir(rtl), [dir="rtl"], [dir="rtl"] *)`)
+	if darkMode == "" || darkMode == DarkModeMedia {
+		ve.addAtRuleVariant("dark", "@media (prefers-color-scheme: dark)")
+	} else {
+		ve.addSelectorVariant("dark", globalSelector(darkMode))
+	}
+	ve.addAtRuleVariant("starting", "@starting-style")
+	ve.addAtRuleVariant("print", "@media print")
+	ve.addAtRuleVariant("forced-colors", "@media (forced-colors: active)")
+
+	// Arbitrary variants: [&>svg], [&_p], [.dark_&], [@supports(display:grid)]
+	// and [@media(any-hover:hover){&:hover}]
+	ve.addFunctionalVariant("", func(scope *RuleScope, value string, v parser.Variant) bool {
+		if v.Value == nil || value == "" {
+			return false
+		}
+		if !strings.HasPrefix(value, "@") {
+			scope.selector(arbitrarySelector(value))
+			return true
+		}
+
+		atRule, selector := value, ""
+		if open := strings.IndexByte(value, '{'); open >= 0 {
+			if !strings.HasSuffix(value, "}") {
+				return false
+			}
+			atRule, selector = value[:open], strings.TrimSpace(value[open+1:len(value)-1])
+		}
+		scope.wrap(arbitraryAtRule(atRule))
+		if selector != "" {
+			scope.selector(arbitrarySelector(selector))
+		}
+		return true
+	})
+}
+
+// initCustomVariants registers the theme's @custom-variant declarations. One
+// redefining a built-in variant, like dark, takes over its place in the order.
+func (ve *VariantEngine) initCustomVariants(variants []parser.CustomVariant) {
+	for _, variant := range variants {
+		rules := make([]string, len(variant.Rules))
+		for i, rule := range variant.Rules {
+			if strings.HasPrefix(rule, "@") {
+				rules[i] = rule
+			} else {
+				rules[i] = globalSelector(rule)
+			}
+		}
+
+		apply := func(scope *RuleScope) {
+			for _, rule := range rules {
+				if strings.HasPrefix(rule, "@") {
+					scope.wrap(rule)
+				} else {
+					scope.selector(rule)
+				}
+			}
+		}
+		if existing, exists := ve.staticVariants[variant.Name]; exists {
+			existing.apply = apply
+		} else {
+			ve.addStaticVariant(variant.Name, apply)
+		}
+	}
+}
+
+// ARIA states with a true value, for aria-* variants
+var ariaStates = map[string]bool{
+	"busy": true, "checked": true, "disabled": true, "expanded": true,
+	"hidden": true, "pressed": true, "readonly": true, "required": true,
+	"selected": true,
+}
+
+// arbitrarySelector turns the value of a variant like group-[.is-published]
+// into a selector template, applying it to "&" unless it says where. Its
+// classes are global, as they come from the markup rather than the module.
+func arbitrarySelector(value string) string {
+	value = globalSelector(value)
+	if strings.Contains(value, "&") {
+		return value
+	}
+	if strings.HasPrefix(value, ":global(") && !strings.Contains(value, ",") && !strings.Contains(value, " ") {
+		return "&" + value
+	}
+	return "&:is(" + value + ")"
+}
+
+func (ve *VariantEngine) addStaticVariant(name string, apply func(scope *RuleScope)) {
+	ve.nextOrder++
+	ve.staticVariants[name] = &variantHandler{order: ve.nextOrder, apply: apply}
+}
+
+// addSelectorVariant registers a variant that rewrites the selector, where
+// "&" in template stands for the selector so far
+func (ve *VariantEngine) addSelectorVariant(name, template string) {
+	ve.addStaticVariant(name, func(scope *RuleScope) {
+		scope.selector(template)
+	})
+}
+
+func (ve *VariantEngine) addAtRuleVariant(name, atRule string) {
+	ve.addStaticVariant(name, func(scope *RuleScope) {
+		scope.wrap(atRule)
+	})
+}
+
+func (ve *VariantEngine) addFunctionalVariant(prefix string, apply func(scope *RuleScope, value string, v parser.Variant) bool) {
+	ve.nextOrder++
+	ve.functionalVariants = append(ve.functionalVariants, &functionalVariant{
+		prefix: prefix,
+		order:  ve.nextOrder,
+		apply:  apply,
+	})
+}
+
+// arbitraryAtRule spaces out an at-rule written without spaces in a class,
+// "@supports(display:grid)" becoming "@supports (display:grid)"
+func arbitraryAtRule(atRule string) string {
+	atRule = strings.TrimSpace(atRule)
+	end := strings.IndexAny(atRule, " (")
+	if end < 0 {
+		return atRule
+	}
+	return atRule[:end] + " " + strings.TrimSpace(atRule[end:])
+}
+
+// relativeSelector turns a selector template into a selector matching the
+// same elements from inside :not() or :has(): "&:hover" becomes ":hover"
+// and ".card:hover &" becomes ".card:hover *"
+func relativeSelector(template string) string {
+	parts := splitSelectorList(template)
+	for i, part := range parts {
+		if strings.HasPrefix(part, "&") && strings.Count(part, "&") == 1 && len(part) > 1 {
+			parts[i] = part[1:]
+		} else {
+			parts[i] = ":is(" + strings.ReplaceAll(part, "&", "*") + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// negateAtRule turns "@media (hover: hover)" into "@media not (hover: hover)",
+// and likewise for @supports and @container
+func negateAtRule(atRule string) (string, bool) {
+	for _, name := range []string{"@media ", "@supports ", "@container "} {
+		if !strings.HasPrefix(atRule, name) {
+			continue
+		}
+		query := strings.TrimPrefix(atRule, name)
+		if strings.HasPrefix(query, "not ") {
+			return name + strings.TrimPrefix(query, "not "), true
+		}
+		if name == "@container " && !strings.HasPrefix(query, "(") {
+			// Named container, "@container sidebar (width >= 32rem)"
+			space := strings.IndexByte(query, ' ')
+			if space < 0 {
+				return "", false
+			}
+			return name + query[:space] + " not " + query[space+1:], true
+		}
+		if strings.Contains(query, ",") || strings.Contains(query, " and ") {
+			query = "(" + query + ")"
+		}
+		return name + "not " + query, true
+	}
+	return "", false
+}
+
+// addContainerVariant registers a container query variant for a named size
+func (ve *VariantEngine) addContainerVariant(name, condition string) {
+	ve.addFunctionalVariant(name, func(scope *RuleScope, value string, v parser.Variant) bool {
+		if value != "" {
+			return false
+		}
+		scope.wrap(containerQuery(v.Modifier, condition))
+		return true
+	})
+}
+
+// containerQuery writes an @container rule, against the named container if
+// there is one
+func containerQuery(name, condition string) string {
+	if name != "" {
+		return "@container " + name + " (" + condition + ")"
+	}
+	return "@container (" + condition + ")"
+}
+
+// addMarkerVariant registers group-* or peer-*. The inner variant's selector
+// is applied to the marked element, which is then joined to the current
+// selector with combinator.
+func (ve *VariantEngine) addMarkerVariant(prefix string, elements func(markers Markers) map[string]string, combinator string) {
+	ve.addCompoundVariant(prefix, func(scope *RuleScope, inner RuleScope, v parser.Variant, markers Markers) bool {
+		marker, exists := elements(markers)[v.Modifier]
+		if !exists {
+			return false
+		}
+		if inner.Selector == "" {
+			inner.Selector = arbitrarySelector(v.Value.Text)
+		}
+		if inner.Selector == "&" || !strings.Contains(inner.Selector, "&") {
+			return false
+		}
+
+		parts := splitSelectorList(inner.Selector)
+		for i, part := range parts {
+			parts[i] = strings.ReplaceAll(part, "&", marker)
+		}
+		target := parts[0]
+		if len(parts) > 1 {
+			target = ":is(" + strings.Join(parts, ", ") + ")"
+		}
+
+		for _, atRule := range inner.AtRules {
+			scope.wrap(atRule)
+		}
+		scope.selector(target + combinator + "&")
+		return true
+	})
+}
+
+// addRelationalVariant registers in-* or has-*, which match an element by
+// another element matching the inner variant or an arbitrary selector. shape
+// turns that element's selector into the variant's selector template.
+func (ve *VariantEngine) addRelationalVariant(prefix string, shape func(target string) string) {
+	ve.addCompoundVariant(prefix, func(scope *RuleScope, inner RuleScope, v parser.Variant, markers Markers) bool {
+		var target string
+		switch {
+		case inner.Selector == "":
+			target = globalSelector(v.Value.Text)
+		case inner.Selector != "&":
+			target = strings.ReplaceAll(inner.Selector, "&", "*")
+		default:
+			return false
+		}
+		for _, atRule := range inner.AtRules {
+			scope.wrap(atRule)
+		}
+		scope.selector(shape(target))
+		return true
+	})
+}
+
+func (ve *VariantEngine) addCompoundVariant(prefix string, apply func(scope *RuleScope, inner RuleScope, v parser.Variant, markers Markers) bool) {
+	ve.nextOrder++
+	ve.compoundVariants = append(ve.compoundVariants, &compoundVariant{
+		prefix: prefix,
+		order:  ve.nextOrder,
+		apply:  apply,
+	})
+}
+
+// selector substitutes the current selector for "&" in template. Each
+// selector of a comma-separated list is substituted on its own.
+func (s *RuleScope) selector(template string) {
+	parts := splitSelectorList(s.Selector)
+	for i, part := range parts {
+		parts[i] = strings.ReplaceAll(template, "&", part)
+	}
+	s.Selector = strings.Join(parts, ", ")
+}
+
+func (s *RuleScope) wrap(atRule string) {
+	s.AtRules = append(s.AtRules, atRule)
+}
+
+// Key identifies the rule the scope's declarations are collected in
+func (s RuleScope) Key() string {
+	return strings.Join(s.AtRules, "\x00") + "\x00" + s.Selector
+}
+
+// Less orders scopes the way Tailwind orders variants: plain rules first,
+// then by the registration order of the variants involved. Stacks of the
+// same variants, hover:md and md:hover, are ordered by their outer variant.
+func (s RuleScope) Less(other RuleScope) bool {
+	if less, decided := compareOrders(sortedDescending(s.Order), sortedDescending(other.Order)); decided {
+		return less
+	}
+	less, _ := compareOrders(s.Order, other.Order)
+	return less
+}
+
+// compareOrders compares two variant orders element by element, the shorter
+// first when one is a prefix of the other. decided is false when they are equal.
+func compareOrders(a, b []int) (less, decided bool) {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i], true
+		}
+	}
+	return len(a) < len(b), len(a) != len(b)
+}
+
+func sortedDescending(orders []int) []int {
+	sorted := make([]int, len(orders))
+	copy(sorted, orders)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	return sorted
+}
+
+// globalSelector marks the classes and attributes of a user-supplied selector
+// template as :global(...), so CSS modules leave the names of ancestors like
+// .dark untouched: ".dark &" becomes ":global(.dark) &".
+func globalSelector(template string) string {
+	if strings.Contains(template, ":global") {
+		return template
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(template); i++ {
+		switch c := template[i]; {
+		case c == '.':
+			end := i + 1
+			for end < len(template) && isIdentByte(template[end]) {
+				end++
+			}
+			out.WriteString(":global(" + template[i:end] + ")")
+			i = end - 1
+		case c == '[':
+			end := strings.IndexByte(template[i:], ']')
+			if end < 0 {
+				out.WriteString(template[i:])
+				return out.String()
+			}
+			out.WriteString(":global(" + template[i:i+end+1] + ")")
+			i += end
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+func isIdentByte(c byte) bool {
+	return c == '-' || c == '_' || c == '\\' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// splitSelectorList splits a selector list at top-level commas
+func splitSelectorList(selector string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(selector[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(selector[start:]))
+}
@@ -0,0 +1,381 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVariantOrder(t *testing.T) {
+	tests := []struct {
+		classes string
+		want    []string // "at-rules { selector }" of each rule, in output order
+	}{
+		{
+			classes: "lg:p-3 md:p-4 sm:p-2 p-1",
+			want: []string{
+				"{ .div_1 }",
+				"@media (width >= 40rem) { .div_1 }",
+				"@media (width >= 48rem) { .div_1 }",
+				"@media (width >= 64rem) { .div_1 }",
+			},
+		},
+		{
+			classes: "max-md:p-1 sm:p-3 max-sm:p-2",
+			want: []string{
+				"@media (width < 48rem) { .div_1 }",
+				"@media (width < 40rem) { .div_1 }",
+				"@media (width >= 40rem) { .div_1 }",
+			},
+		},
+		{
+			classes: "focus:p-6 hover:p-2 p-1",
+			want: []string{
+				"{ .div_1 }",
+				"@media (hover: hover) { .div_1:hover }",
+				"{ .div_1:focus }",
+			},
+		},
+		{
+			classes: "dark:p-7 md:p-4 hover:p-2",
+			want: []string{
+				"@media (hover: hover) { .div_1:hover }",
+				"@media (width >= 48rem) { .div_1 }",
+				"@media (prefers-color-scheme: dark) { .div_1 }",
+			},
+		},
+		{
+			classes: "md:hover:p-8 hover:md:p-9",
+			want: []string{
+				"@media (hover: hover) @media (width >= 48rem) { .div_1:hover }",
+				"@media (width >= 48rem) @media (hover: hover) { .div_1:hover }",
+			},
+		},
+		{
+			classes: "hover:md:p-9 md:hover:p-8",
+			want: []string{
+				"@media (hover: hover) @media (width >= 48rem) { .div_1:hover }",
+				"@media (width >= 48rem) @media (hover: hover) { .div_1:hover }",
+			},
+		},
+		{
+			classes: "hover:before:p-2 before:p-1 p-3",
+			want: []string{
+				"{ .div_1 }",
+				"{ .div_1::before }",
+				"@media (hover: hover) { .div_1:hover::before }",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		checkScopes(t, tt.classes, tt.want)
+	}
+}
+
+// checkScopes checks the "at-rules { selector }" of the rules the classes of
+// a div convert to, in output order, leaving out @property rules
+func checkScopes(t *testing.T, classes string, want []string) {
+	t.Helper()
+	if got := scopes(convert(t, classes)); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("%q:\n%s\nwant:\n%s", classes, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// scopes renders the at-rules and selector of each rule, leaving out
+// @property rules
+func scopes(rules []CSSRule) []string {
+	var lines []string
+	for _, rule := range rules {
+		if strings.HasPrefix(rule.Selector, "@property") {
+			continue
+		}
+		lines = append(lines, strings.TrimSpace(strings.Join(rule.AtRules, " ")+" { "+rule.Selector+" }"))
+	}
+	return lines
+}
+
+func TestStructuralVariants(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string
+	}{
+		{"not-first:p-1", "{ .div_1:not(:first-child) }"},
+		{"not-hover:p-1", "{ .div_1:not(:hover) }"},
+		{"not-[.active]:p-1", "{ .div_1:not(:global(.active)) }"},
+		{"not-supports-grid:p-1", "@supports not (grid: var(--tw)) { .div_1 }"},
+		{"not-md:p-1", "@media not (width >= 48rem) { .div_1 }"},
+		{"in-focus:p-1", "{ :where(*:focus) .div_1 }"},
+		{"in-[.dark]:p-1", "{ :where(:global(.dark)) .div_1 }"},
+		{"has-checked:p-1", "{ .div_1:has(*:checked) }"},
+		{"has-[img]:p-1", "{ .div_1:has(img) }"},
+		{"has-hover:p-1", "@media (hover: hover) { .div_1:has(*:hover) }"},
+		{"aria-checked:p-1", `{ .div_1[aria-checked="true"] }`},
+		{"aria-[sort=ascending]:p-1", "{ .div_1[aria-sort=ascending] }"},
+		{"data-active:p-1", "{ .div_1[data-active] }"},
+		{"data-[state=open]:p-1", "{ .div_1[data-state=open] }"},
+		{"nth-3:p-1", "{ .div_1:nth-child(3) }"},
+		{"nth-last-[2n+1]:p-1", "{ .div_1:nth-last-child(2n+1) }"},
+		{"nth-of-type-2:p-1", "{ .div_1:nth-of-type(2) }"},
+		{"nth-last-of-type-[odd]:p-1", "{ .div_1:nth-last-of-type(odd) }"},
+	}
+
+	for _, tt := range tests {
+		checkScopes(t, tt.class, []string{tt.want})
+	}
+}
+
+func TestArbitraryVariants(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string
+	}{
+		{"[&>svg]:p-1", "{ .div_1>svg }"},
+		{"[&_p]:mt-2", "{ .div_1 p }"},
+		{"[&:nth-child(3)]:p-2", "{ .div_1:nth-child(3) }"},
+		{"[.dark_&]:p-3", "{ :global(.dark) .div_1 }"},
+		{"[@supports(display:grid)]:grid", "@supports (display:grid) { .div_1 }"},
+		{"[@media(min-width:900px)]:p-5", "@media (min-width:900px) { .div_1 }"},
+		{"*:p-1", "{ :is(.div_1 > *) }"},
+		{"**:p-2", "{ :is(.div_1 *) }"},
+		{"hover:*:p-3", "@media (hover: hover) { :is(.div_1:hover > *) }"},
+		{"*:hover:p-4", "@media (hover: hover) { :is(.div_1 > *):hover }"},
+	}
+
+	for _, tt := range tests {
+		checkScopes(t, tt.class, []string{tt.want})
+	}
+}
+
+func TestPseudoElementVariants(t *testing.T) {
+	tests := []struct {
+		classes    string
+		want       string
+		properties []string
+	}{
+		{
+			classes:    "after:absolute",
+			want:       "{ .div_1::after } content: var(--tw-content); position: absolute",
+			properties: []string{"--tw-content"},
+		},
+		{
+			classes: "before:content-['x'] before:block",
+			want:    "{ .div_1::before } display: block; --tw-content: 'x'; content: var(--tw-content)",
+		},
+		{
+			classes: "hover:before:content-none",
+			want:    "@media (hover: hover) { .div_1:hover::before } --tw-content: none; content: var(--tw-content)",
+		},
+		{
+			classes:    "placeholder:text-gray-400",
+			want:       "{ .div_1::placeholder } color: oklch(70.7% 0.022 261.325)",
+			properties: []string{},
+		},
+		{
+			classes: "file:mr-4",
+			want:    "{ .div_1::file-selector-button } margin-right: 1rem",
+		},
+		{
+			classes: "marker:text-red-500",
+			want:    "{ .div_1 *::marker, .div_1::marker } color: oklch(63.7% 0.237 25.331)",
+		},
+		{
+			classes: "selection:bg-red-500",
+			want:    "{ .div_1 *::selection, .div_1::selection } background-color: oklch(63.7% 0.237 25.331)",
+		},
+		{
+			classes: "backdrop:bg-black/50",
+			want:    "{ .div_1::backdrop } background-color: color-mix(in oklab, #000 50%, transparent)",
+		},
+	}
+
+	for _, tt := range tests {
+		checkRender(t, tt.classes, []string{tt.want}, tt.properties)
+	}
+}
+
+func TestGroupAndPeerVariants(t *testing.T) {
+	tests := []struct {
+		name        string
+		markup      string
+		want        []string
+		diagnostics []string // "class: message"
+	}{
+		{
+			name:   "group",
+			markup: `<div class="group"><p class="group-hover:p-1 group-focus:p-2"></p></div>`,
+			want: []string{
+				"@media (hover: hover) { .div_1:hover .p_layout_2 }",
+				"{ .div_1:focus .p_layout_2 }",
+			},
+		},
+		{
+			name:   "group with arbitrary and functional variants",
+			markup: `<div class="group"><p class="group-[.active]:p-1 group-aria-checked:p-2"></p></div>`,
+			want: []string{
+				"{ .div_1:global(.active) .p_layout_2 }",
+				`{ .div_1[aria-checked="true"] .p_layout_2 }`,
+			},
+		},
+		{
+			name:   "named groups",
+			markup: `<div class="group/item"><div class="group/card"><p class="group-hover/item:p-1 group-hover/card:p-2 group-hover:p-3"></p></div></div>`,
+			want: []string{
+				"{ .p_layout_3 }",
+				"@media (hover: hover) { .div_1:hover .p_layout_3 }",
+				"@media (hover: hover) { .div_2:hover .p_layout_3 }",
+			},
+			diagnostics: []string{`group-hover:p-3: no element marked "group" for "group-hover"`},
+		},
+		{
+			name:   "nearest group",
+			markup: `<div class="group"><div class="group"><p class="group-hover:p-1"></p></div></div>`,
+			want:   []string{"@media (hover: hover) { .div_2:hover .p_layout_3 }"},
+		},
+		{
+			name:        "missing group",
+			markup:      `<p class="group-hover:p-1"></p>`,
+			want:        []string{"{ .p_layout_1 }"},
+			diagnostics: []string{`group-hover:p-1: no element marked "group" for "group-hover"`},
+		},
+		{
+			name:   "peer",
+			markup: `<div><input class="peer"/><span></span><p class="peer-checked:p-1"></p></div>`,
+			want:   []string{"{ .input_1:checked ~ .p_layout_2 }"},
+		},
+		{
+			name:   "nearest preceding peer",
+			markup: `<div><input class="peer"/><input class="peer"/><p class="peer-focus:p-1"></p></div>`,
+			want:   []string{"{ .input_2:focus ~ .p_layout_3 }"},
+		},
+		{
+			name:   "named peer",
+			markup: `<div><input class="peer/email"/><p class="peer-invalid/email:p-2"></p></div>`,
+			want:   []string{"{ .input_1:invalid ~ .p_layout_2 }"},
+		},
+		{
+			name:        "peer that is not a sibling",
+			markup:      `<div><div><p class="peer"></p></div><p class="peer-hover:p-1"></p></div>`,
+			want:        []string{"{ .p_layout_2 }"},
+			diagnostics: []string{`peer-hover:p-1: no element marked "peer" for "peer-hover"`},
+		},
+	}
+
+	for _, tt := range tests {
+		rules, diagnostics := convertMarkup(t, tt.markup)
+		if got := scopes(rules); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.name, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+		var got []string
+		for _, diagnostic := range diagnostics {
+			got = append(got, diagnostic.Class+": "+diagnostic.Message)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.diagnostics, "\n") {
+			t.Errorf("%s: diagnostics %q, want %q", tt.name, got, tt.diagnostics)
+		}
+	}
+}
+
+func TestContainerQueries(t *testing.T) {
+	markers := []struct {
+		class string
+		want  string
+	}{
+		{"@container", "container-type: inline-size"},
+		{"@container/sidebar", "container-type: inline-size\ncontainer-name: sidebar"},
+		{"@container-size", "container-type: size"},
+		{"@container-normal/main", "container-type: normal\ncontainer-name: main"},
+	}
+	for _, tt := range markers {
+		if rules := convert(t, tt.class); len(rules) != 1 || ruleText(rules[0]) != tt.want {
+			t.Errorf("%s: got %+v, want %q", tt.class, rules, tt.want)
+		}
+	}
+
+	variants := []struct {
+		class string
+		want  string
+	}{
+		{"@md:p-1", "@container (width >= 28rem) { .div_1 }"},
+		{"@max-md:p-1", "@container (width < 28rem) { .div_1 }"},
+		{"@lg/sidebar:p-1", "@container sidebar (width >= 32rem) { .div_1 }"},
+		{"@[400px]:p-1", "@container (width >= 400px) { .div_1 }"},
+		{"@min-[30rem]:p-1", "@container (width >= 30rem) { .div_1 }"},
+		{"@max-[20rem]/main:p-1", "@container main (width < 20rem) { .div_1 }"},
+		{"md:@sm:p-1", "@media (width >= 48rem) @container (width >= 24rem) { .div_1 }"},
+	}
+	for _, tt := range variants {
+		checkScopes(t, tt.class, []string{tt.want})
+	}
+}
+
+func TestDarkMode(t *testing.T) {
+	tests := []struct {
+		mode string
+		want []string // Scopes of dark:p-1 dark:hover:p-2, empty if the mode is invalid
+	}{
+		{"", []string{
+			"@media (prefers-color-scheme: dark) { .div_1 }",
+			"@media (prefers-color-scheme: dark) @media (hover: hover) { .div_1:hover }",
+		}},
+		{"media", []string{
+			"@media (prefers-color-scheme: dark) { .div_1 }",
+			"@media (prefers-color-scheme: dark) @media (hover: hover) { .div_1:hover }",
+		}},
+		{"class", []string{
+			"{ :global(.dark) .div_1 }",
+			"@media (hover: hover) { :global(.dark) .div_1:hover }",
+		}},
+		{"[data-theme=dark]", []string{
+			"{ :global([data-theme=dark]) .div_1 }",
+			"@media (hover: hover) { :global([data-theme=dark]) .div_1:hover }",
+		}},
+		{"&:where(.dark, .dark *)", []string{
+			"{ .div_1:where(:global(.dark), :global(.dark) *) }",
+			"@media (hover: hover) { .div_1:where(:global(.dark), :global(.dark) *):hover }",
+		}},
+		{"a { color: red }", nil},
+	}
+
+	for _, tt := range tests {
+		mode, err := ParseDarkMode(tt.mode)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("ParseDarkMode(%q) = %q, want an error", tt.mode, mode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDarkMode(%q): %v", tt.mode, err)
+			continue
+		}
+		options := DefaultOptions()
+		options.DarkMode = mode
+		if got := scopes(convertWith(t, options, "dark:p-1 dark:hover:p-2")); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("dark mode %q:\n%s\nwant:\n%s", tt.mode, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestCustomVariants(t *testing.T) {
+	tests := []struct {
+		css   string
+		class string
+		want  string
+	}{
+		{"@custom-variant dark (&:where(.dark, .dark *));", "dark:p-1", "{ .div_1:where(:global(.dark), :global(.dark) *) }"},
+		{"@custom-variant dark { &:where(.dark, .dark *) { @slot; } }", "dark:p-1", "{ .div_1:where(:global(.dark), :global(.dark) *) }"},
+		{"@custom-variant theme-midnight (&:where([data-theme=midnight] *));", "theme-midnight:p-1", "{ .div_1:where(:global([data-theme=midnight]) *) }"},
+		{"@custom-variant pointer-coarse (@media (pointer: coarse));", "pointer-coarse:p-1", "@media (pointer: coarse) { .div_1 }"},
+		{"@custom-variant hocus { @media (hover: hover) { &:hover, &:focus { @slot; } } }", "hocus:p-1", "@media (hover: hover) { .div_1:hover, .div_1:focus }"},
+	}
+
+	for _, tt := range tests {
+		options := DefaultOptions()
+		if err := options.Theme.Parse(tt.css); err != nil {
+			t.Fatalf("%s: %v", tt.css, err)
+		}
+		if got := scopes(convertWith(t, options, tt.class)); strings.Join(got, "\n") != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.css, strings.Join(got, "\n"), tt.want)
+		}
+	}
+}
@@ -19,9 +19,12 @@ func (g *CSSGenerator) Generate(rules []converter.CSSRule, outputPath string) er
 	cssContent.WriteString("/* Generated CSS Module */\n")
 	cssContent.WriteString("/* Converted from Tailwind CSS classes */\n\n")
 
-	// Generate CSS rules
-	for _, rule := range rules {
-		g.writeRule(&cssContent, rule)
+	// Generate CSS rules; consecutive rules inside the same at-rules, like
+	// the steps of an @keyframes, share one block
+	for i, rule := range rules {
+		open := i == 0 || !sameAtRules(rules[i-1], rule)
+		close := i == len(rules)-1 || !sameAtRules(rule, rules[i+1])
+		g.writeRule(&cssContent, rule, open, close)
 		cssContent.WriteString("\n")
 	}
 
@@ -29,24 +32,46 @@ func (g *CSSGenerator) Generate(rules []converter.CSSRule, outputPath string) er
 	return os.WriteFile(outputPath, []byte(cssContent.String()), 0644)
 }
 
-func (g *CSSGenerator) writeRule(builder *strings.Builder, rule converter.CSSRule) {
+// sameAtRules reports whether two rules sit inside the same at-rules
+func sameAtRules(a, b converter.CSSRule) bool {
+	if len(a.AtRules) == 0 || len(a.AtRules) != len(b.AtRules) {
+		return false
+	}
+	for i := range a.AtRules {
+		if a.AtRules[i] != b.AtRules[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeRule writes a rule, opening its at-rules if open is set and closing
+// them if close is set
+func (g *CSSGenerator) writeRule(builder *strings.Builder, rule converter.CSSRule, open, close bool) {
+	// Open the enclosing at-rules, outermost first
+	indent := strings.Repeat("  ", len(rule.AtRules))
+	if open {
+		indent = ""
+		for _, atRule := range rule.AtRules {
+			builder.WriteString(indent)
+			builder.WriteString(atRule)
+			builder.WriteString(" {\n")
+			indent += "  "
+		}
+	}
+
 	// Write selector
+	builder.WriteString(indent)
 	builder.WriteString(rule.Selector)
 	builder.WriteString(" {\n")
 
-	// Separate properties into regular properties, media queries, hover states, and comments
+	// Separate properties into regular properties and comments
 	var regularProps []converter.CSSProperty
-	var mediaQueries []converter.CSSProperty
-	var hoverStates []converter.CSSProperty
 	var comments []converter.CSSProperty
 
 	for _, prop := range rule.Properties {
 		if strings.HasPrefix(prop.Name, "/*") {
 			comments = append(comments, prop)
-		} else if strings.HasPrefix(prop.Name, "@media") {
-			mediaQueries = append(mediaQueries, prop)
-		} else if strings.HasPrefix(prop.Name, ":hover") {
-			hoverStates = append(hoverStates, prop)
 		} else if strings.HasPrefix(prop.Name, "@") {
 			// Other at-rules, write as comments for now
 			comments = append(comments, converter.CSSProperty{
@@ -60,6 +85,7 @@ func (g *CSSGenerator) writeRule(builder *strings.Builder, rule converter.CSSRul
 
 	// Write regular properties
 	for _, prop := range regularProps {
+		builder.WriteString(indent)
 		builder.WriteString("  ")
 		builder.WriteString(prop.Name)
 		builder.WriteString(": ")
@@ -69,6 +95,7 @@ func (g *CSSGenerator) writeRule(builder *strings.Builder, rule converter.CSSRul
 
 	// Write comments
 	for _, comment := range comments {
+		builder.WriteString(indent)
 		builder.WriteString("  ")
 		builder.WriteString(comment.Name)
 		if comment.Value != "" {
@@ -78,85 +105,21 @@ func (g *CSSGenerator) writeRule(builder *strings.Builder, rule converter.CSSRul
 		builder.WriteString("\n")
 	}
 
+	builder.WriteString(indent)
 	builder.WriteString("}")
 
-	// Write hover states as separate rules
-	if len(hoverStates) > 0 {
-		builder.WriteString("\n\n")
-		builder.WriteString(rule.Selector)
-		builder.WriteString(":hover {\n")
-		for _, prop := range hoverStates {
-			builder.WriteString("  ")
-			builder.WriteString(prop.Value)
-			builder.WriteString(";\n")
-		}
-		builder.WriteString("}")
+	// Close the at-rules
+	if !close {
+		return
 	}
-
-	// Write media queries as separate rules
-	for _, mediaQuery := range mediaQueries {
-		builder.WriteString("\n\n")
-		builder.WriteString(mediaQuery.Name)
-		builder.WriteString(" {\n")
-		builder.WriteString("  ")
-		builder.WriteString(rule.Selector)
-		builder.WriteString(" {\n")
-		builder.WriteString("    ")
-		builder.WriteString(mediaQuery.Value)
-		builder.WriteString(";\n")
-		builder.WriteString("  }\n")
+	for range rule.AtRules {
+		indent = indent[2:]
+		builder.WriteString("\n")
+		builder.WriteString(indent)
 		builder.WriteString("}")
 	}
 }
 
-func (g *CSSGenerator) writeAtRule(builder *strings.Builder, prop converter.CSSProperty, rule converter.CSSRule) {
-	switch {
-	case strings.HasPrefix(prop.Name, "@media"):
-		g.writeMediaQuery(builder, prop, rule)
-	case strings.HasPrefix(prop.Name, "@container"):
-		g.writeContainerQuery(builder, prop, rule)
-	case strings.HasPrefix(prop.Name, "@layer"):
-		g.writeCascadeLayer(builder, prop, rule)
-	default:
-		// Unknown at-rule, write as comment
-		builder.WriteString("  /* ")
-		builder.WriteString(prop.Name)
-		builder.WriteString(": ")
-		builder.WriteString(prop.Value)
-		builder.WriteString(" */\n")
-	}
-}
-
-func (g *CSSGenerator) writeMediaQuery(builder *strings.Builder, prop converter.CSSProperty, rule converter.CSSRule) {
-	builder.WriteString("  ")
-	builder.WriteString(prop.Name)
-	builder.WriteString(" ")
-	builder.WriteString(prop.Value)
-	builder.WriteString(" {\n")
-
-	// Write properties inside media query
-	builder.WriteString("    /* Media query content would go here */\n")
-
-	builder.WriteString("  }\n")
-}
-
-func (g *CSSGenerator) writeContainerQuery(builder *strings.Builder, prop converter.CSSProperty, rule converter.CSSRule) {
-	builder.WriteString("  @container ")
-	builder.WriteString(prop.Value)
-	builder.WriteString(" {\n")
-
-	// Write properties inside container query
-	builder.WriteString("    /* Container query content would go here */\n")
-
-	builder.WriteString("  }\n")
-}
-
-func (g *CSSGenerator) writeCascadeLayer(builder *strings.Builder, prop converter.CSSProperty, rule converter.CSSRule) {
-	builder.WriteString("  /* @layer ")
-	builder.WriteString(prop.Value)
-	builder.WriteString(" */\n")
-}
-
 func (g *CSSGenerator) GenerateWithOptions(rules []converter.CSSRule, outputPath string, options CSSOptions) error {
 	var cssContent strings.Builder
 
@@ -198,11 +161,21 @@ func (g *CSSGenerator) GenerateWithOptions(rules []converter.CSSRule, outputPath
 func (g *CSSGenerator) writeFormattedRule(builder *strings.Builder, rule converter.CSSRule, indentSize int) {
 	indent := strings.Repeat(" ", indentSize)
 
+	outer := ""
+	for _, atRule := range rule.AtRules {
+		builder.WriteString(outer)
+		builder.WriteString(atRule)
+		builder.WriteString(" {\n")
+		outer += indent
+	}
+
+	builder.WriteString(outer)
 	builder.WriteString(rule.Selector)
 	builder.WriteString(" {\n")
 
 	for _, prop := range rule.Properties {
 		if !strings.HasPrefix(prop.Name, "/*") && !strings.HasPrefix(prop.Name, "@") {
+			builder.WriteString(outer)
 			builder.WriteString(indent)
 			builder.WriteString(prop.Name)
 			builder.WriteString(": ")
@@ -211,11 +184,23 @@ func (g *CSSGenerator) writeFormattedRule(builder *strings.Builder, rule convert
 		}
 	}
 
+	builder.WriteString(outer)
 	builder.WriteString("}")
 	builder.WriteString("\n")
+
+	for range rule.AtRules {
+		outer = outer[len(indent):]
+		builder.WriteString(outer)
+		builder.WriteString("}\n")
+	}
 }
 
 func (g *CSSGenerator) writeMinifiedRule(builder *strings.Builder, rule converter.CSSRule) {
+	for _, atRule := range rule.AtRules {
+		builder.WriteString(atRule)
+		builder.WriteString("{")
+	}
+
 	builder.WriteString(rule.Selector)
 	builder.WriteString("{")
 
@@ -229,6 +214,7 @@ func (g *CSSGenerator) writeMinifiedRule(builder *strings.Builder, rule converte
 	}
 
 	builder.WriteString("}")
+	builder.WriteString(strings.Repeat("}", len(rule.AtRules)))
 }
 
 type CSSOptions struct {
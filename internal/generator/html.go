@@ -4,19 +4,24 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"tailwind-v4-to-css-converter/converter"
 	"tailwind-v4-to-css-converter/internal/parser"
 )
 
 type HTMLGenerator struct {
-	classRegex *regexp.Regexp
+	htmlParser *parser.HTMLParser // Re-parses content changed by a template pre-processor
 }
 
 func NewHTMLGenerator() *HTMLGenerator {
-	return &HTMLGenerator{
-		classRegex: regexp.MustCompile(`(class|className)=["']([^"']+)["']`),
-	}
+	return &HTMLGenerator{htmlParser: parser.NewHTMLParser()}
+}
+
+// SetParser sets the parser used to re-parse pre-processed content. It should
+// be the one that parsed the document, so it finds the same class helpers.
+func (g *HTMLGenerator) SetParser(htmlParser *parser.HTMLParser) {
+	g.htmlParser = htmlParser
 }
 
 func (g *HTMLGenerator) Generate(document *parser.Document, semanticMappings []converter.SemanticMapping, outputPath, moduleName string) error {
@@ -30,83 +35,161 @@ func (g *HTMLGenerator) Generate(document *parser.Document, semanticMappings []c
 	return os.WriteFile(outputPath, []byte(updatedContent), 0644)
 }
 
+// refReplacement is what one class string turns into: the semantic class
+// taking over its Tailwind classes, if any, and the classes it keeps
+type refReplacement struct {
+	semanticName     string
+	remainingClasses []string
+}
+
 func (g *HTMLGenerator) updateClassReferences(document *parser.Document, semanticMappings []converter.SemanticMapping, moduleName string) string {
 	content := document.Content
 
-	// Create a mapping from original classes to semantic names
-	classMap := make(map[string]string)
+	// The first class string of each element group carries the semantic
+	// class; the group's other strings only keep their non-Tailwind classes
+	replacements := make(map[int]refReplacement)
+	var refs []parser.ClassRef
 	for _, mapping := range semanticMappings {
-		classMap[mapping.OriginalClasses] = mapping.SemanticName
+		converted := strings.Fields(mapping.OriginalClasses)
+		for i, ref := range mapping.Refs {
+			replacement := refReplacement{
+				remainingClasses: g.remainingClasses(content[ref.Start:ref.End], converted),
+			}
+			if i == 0 {
+				replacement.semanticName = mapping.SemanticName
+			}
+			replacements[ref.Start] = replacement
+			refs = append(refs, ref)
+		}
 	}
 
-	// Process each class reference
-	result := g.classRegex.ReplaceAllStringFunc(content, func(match string) string {
-		return g.replaceClassAttribute(match, classMap, moduleName)
+	// Rewrite each class string at the offsets recorded by the parser
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Start < refs[j].Start
 	})
 
-	return result
+	var result strings.Builder
+	last := 0
+	for _, ref := range refs {
+		start, end := g.refSpan(ref)
+		if start < last {
+			continue
+		}
+
+		replacement := replacements[ref.Start]
+		rewritten := g.replaceClassRef(ref, replacement.semanticName, replacement.remainingClasses)
+		if rewritten == "" && ref.Kind == parser.TemplateRef {
+			start, end = g.templateGap(content, start, end, last)
+		}
+		result.WriteString(content[last:start])
+		result.WriteString(rewritten)
+		last = end
+	}
+	result.WriteString(content[last:])
+
+	return result.String()
 }
 
-func (g *HTMLGenerator) replaceClassAttribute(classAttr string, classMap map[string]string, moduleName string) string {
-	// Extract the attribute name and class values
-	parts := g.classRegex.FindStringSubmatch(classAttr)
-	if len(parts) < 3 {
-		return classAttr
+// refSpan returns the part of the source replaced when rewriting ref
+func (g *HTMLGenerator) refSpan(ref parser.ClassRef) (int, int) {
+	switch ref.Kind {
+	case parser.AttributeRef:
+		return ref.AttrStart, ref.ValueEnd
+	case parser.TemplateRef:
+		return ref.Start, ref.End
 	}
 
-	attrName := parts[1] // "class" or "className"
-	classValues := parts[2]
+	// String literals and object keys, including their quotes
+	if ref.Quote == 0 {
+		return ref.Start, ref.End
+	}
+	return ref.Start - 1, ref.End + 1
+}
 
-	// Split class values
-	classes := strings.Fields(classValues)
+// templateGap widens the span of a template chunk left empty over the
+// whitespace separating it from the rest of the template: the space after
+// it, and the space before it too when it ends the template
+func (g *HTMLGenerator) templateGap(content string, start, end, last int) (int, int) {
+	for end < len(content) && isSpace(content[end]) {
+		end++
+	}
+	if end < len(content) && content[end] == '`' {
+		for start > last && isSpace(content[start-1]) {
+			start--
+		}
+	}
+	return start, end
+}
 
-	// Create sets to track processed classes and avoid duplicates
-	processedTailwindClasses := make(map[string]bool)
-	var remainingClasses []string
-	var semanticClasses []string
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
 
-	// Find the best matching semantic mapping for this set of classes
-	var bestMatch string
-	var bestMatchClasses []string
-	maxMatches := 0
+// remainingClasses returns the classes of classValues that were not
+// converted and have to stay in the markup.
+func (g *HTMLGenerator) remainingClasses(classValues string, converted []string) []string {
+	var remaining []string
+	for _, class := range strings.Fields(classValues) {
+		if !g.containsClass(converted, class) {
+			remaining = append(remaining, class)
+		}
+	}
+	return remaining
+}
 
-	for originalClasses, semanticName := range classMap {
-		originalClassList := strings.Fields(originalClasses)
-		matchCount := 0
+func (g *HTMLGenerator) replaceClassRef(ref parser.ClassRef, semanticName string, remainingClasses []string) string {
+	remaining := strings.Join(remainingClasses, " ")
+	styleRef := ""
+	if semanticName != "" {
+		styleRef = fmt.Sprintf("styles.%s", semanticName)
+	}
 
-		// Count how many classes from this element match the mapping
-		for _, class := range classes {
-			if g.containsClass(originalClassList, class) && g.isTailwindClass(class) {
-				matchCount++
-			}
+	switch ref.Kind {
+	case parser.AttributeRef:
+		return g.replaceClassAttribute(ref.Attr, styleRef, remainingClasses)
+
+	case parser.TemplateRef:
+		// Inside a template literal: `p-4 ${x}` becomes `${styles.a} ${x}`
+		switch {
+		case styleRef == "":
+			return remaining
+		case remaining == "":
+			return "${" + styleRef + "}"
+		default:
+			return remaining + " ${" + styleRef + "}"
 		}
 
-		// Use the mapping with the most matches
-		if matchCount > maxMatches {
-			maxMatches = matchCount
-			bestMatch = semanticName
-			bestMatchClasses = originalClassList
+	case parser.ObjectKeyRef:
+		// {"bg-red-500": hasError} becomes {[styles.a]: hasError}
+		switch {
+		case styleRef == "":
+			return g.quote(ref.Quote, remaining)
+		case remaining == "":
+			return "[" + styleRef + "]"
+		default:
+			return "[`" + remaining + " ${" + styleRef + "}`]"
 		}
 	}
 
-	// Apply the best match if found
-	if bestMatch != "" && maxMatches > 0 {
-		// Mark matched Tailwind classes as processed
-		for _, class := range bestMatchClasses {
-			if g.containsClass(classes, class) {
-				processedTailwindClasses[class] = true
-			}
-		}
-		semanticClasses = append(semanticClasses, fmt.Sprintf("styles.%s", bestMatch))
+	// A string literal inside an expression, e.g. cn("flex", isActive && "bg-blue-500")
+	switch {
+	case styleRef == "":
+		return g.quote(ref.Quote, remaining)
+	case remaining == "":
+		return styleRef
+	default:
+		return "`" + remaining + " ${" + styleRef + "}`"
 	}
+}
 
-	// Add remaining non-Tailwind classes and unprocessed Tailwind classes
-	for _, class := range classes {
-		if !processedTailwindClasses[class] && !g.isTailwindClass(class) {
-			remainingClasses = append(remainingClasses, class)
-		}
+func (g *HTMLGenerator) quote(quote byte, value string) string {
+	if quote == 0 {
+		quote = '"'
 	}
+	return string(quote) + value + string(quote)
+}
 
+func (g *HTMLGenerator) replaceClassAttribute(attrName, styleRef string, remainingClasses []string) string {
 	// Build the final class attribute
 	var finalClasses []string
 
@@ -116,7 +199,9 @@ func (g *HTMLGenerator) replaceClassAttribute(classAttr string, classMap map[str
 	}
 
 	// Add semantic classes
-	finalClasses = append(finalClasses, semanticClasses...)
+	if styleRef != "" {
+		finalClasses = append(finalClasses, styleRef)
+	}
 
 	// Generate the new class attribute
 	if len(finalClasses) == 0 {
@@ -142,25 +227,6 @@ func (g *HTMLGenerator) containsClass(classList []string, targetClass string) bo
 	return false
 }
 
-func (g *HTMLGenerator) isTailwindClass(class string) bool {
-	// Same logic as in parser/html.go
-	tailwindPrefixes := []string{
-		"flex", "grid", "block", "inline", "hidden",
-		"text-", "bg-", "border-", "p-", "m-", "w-", "h-",
-		"items-", "justify-", "gap-", "space-", "rounded",
-		"font-", "leading-", "tracking-", "opacity-",
-		"hover:", "focus:", "active:", "disabled:",
-		"sm:", "md:", "lg:", "xl:", "2xl:",
-	}
-
-	for _, prefix := range tailwindPrefixes {
-		if strings.HasPrefix(class, prefix) || class == strings.TrimSuffix(prefix, "-") {
-			return true
-		}
-	}
-	return false
-}
-
 func (g *HTMLGenerator) addCSSModuleImport(content, moduleName string) string {
 	// Check if import already exists
 	importRegex := regexp.MustCompile(`import\s+.*from\s+['"].*\.module\.css['"]`)
@@ -229,8 +295,23 @@ func (g *HTMLGenerator) GenerateWithCustomTemplate(document *parser.Document, se
 		content = template.PreProcessor(content)
 	}
 
+	// Offsets are only valid for the original content, so re-parse if the
+	// pre-processor changed it and move the mappings over to the new offsets
+	updatedDocument := document
+	if content != document.Content {
+		reparsed, err := g.htmlParser.ParseContent(content)
+		if err != nil {
+			return err
+		}
+		semanticMappings, err = g.remapRefs(document, reparsed, semanticMappings)
+		if err != nil {
+			return err
+		}
+		updatedDocument = reparsed
+	}
+
 	// Update class references
-	updatedContent := g.updateClassReferences(&parser.Document{Content: content, ClassRefs: document.ClassRefs}, semanticMappings, moduleName)
+	updatedContent := g.updateClassReferences(updatedDocument, semanticMappings, moduleName)
 
 	// Add imports with custom logic
 	if template.ImportGenerator != nil {
@@ -247,6 +328,29 @@ func (g *HTMLGenerator) GenerateWithCustomTemplate(document *parser.Document, se
 	return os.WriteFile(outputPath, []byte(updatedContent), 0644)
 }
 
+// remapRefs points the mappings at the class strings of the re-parsed
+// document, which must contain the same class strings in the same order.
+func (g *HTMLGenerator) remapRefs(original, reparsed *parser.Document, semanticMappings []converter.SemanticMapping) ([]converter.SemanticMapping, error) {
+	if len(original.ClassRefs) != len(reparsed.ClassRefs) {
+		return nil, fmt.Errorf("pre-processor changed the class attributes (%d before, %d after)", len(original.ClassRefs), len(reparsed.ClassRefs))
+	}
+
+	ordinal := make(map[int]int)
+	for i, ref := range original.ClassRefs {
+		ordinal[ref.Start] = i
+	}
+
+	remapped := make([]converter.SemanticMapping, len(semanticMappings))
+	for i, mapping := range semanticMappings {
+		remapped[i] = mapping
+		remapped[i].Refs = make([]parser.ClassRef, len(mapping.Refs))
+		for j, ref := range mapping.Refs {
+			remapped[i].Refs[j] = reparsed.ClassRefs[ordinal[ref.Start]]
+		}
+	}
+	return remapped, nil
+}
+
 type HTMLTemplate struct {
 	PreProcessor    func(string) string
 	ImportGenerator func(content, moduleName string) string
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tailwind-v4-to-css-converter/converter"
+	"tailwind-v4-to-css-converter/internal/parser"
+)
+
+func TestCustomTemplateKeepsClassHelpers(t *testing.T) {
+	htmlParser := parser.NewHTMLParser()
+	htmlParser.SetClassHelpers([]string{"tw"})
+	document, err := htmlParser.ParseContent(`export const A = () => <div className={tw("p-4", "m-2")} />;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, mappings := converter.NewConverter().Convert(parser.NewClassExtractor().Extract(document))
+
+	g := NewHTMLGenerator()
+	g.SetParser(htmlParser)
+	path := filepath.Join(t.TempDir(), "a.tsx")
+	template := HTMLTemplate{PreProcessor: func(content string) string {
+		return "// generated\n" + content
+	}}
+	if err := g.GenerateWithCustomTemplate(document, mappings, path, "a", template); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(output), "p-4") || !strings.Contains(string(output), "styles.") {
+		t.Errorf("classes passed to tw() were not rewritten:\n%s", output)
+	}
+}
+
+func TestTemplateChunksLeaveNoWhitespace(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{
+			content: "const A = () => <div className={`p-4 ${x} m-2`} />;",
+			want:    "const A = () => <div className={`${styles.div_1} ${x}`} />;",
+		},
+		{
+			content: "const A = () => <div className={`p-4 ${x} m-2 ${y} flex`} />;",
+			want:    "const A = () => <div className={`${styles.div_1} ${x} ${y}`} />;",
+		},
+		{
+			content: "const A = () => <div className={`card p-4 ${x} m-2 `} />;",
+			want:    "const A = () => <div className={`card ${styles.div_1} ${x}`} />;",
+		},
+		{
+			content: "const A = () => <div className={`p-4 ${x} card m-2`} />;",
+			want:    "const A = () => <div className={`${styles.div_1} ${x} card`} />;",
+		},
+	}
+
+	for _, tt := range tests {
+		document, err := parser.NewHTMLParser().ParseContent(tt.content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, mappings := converter.NewConverter().Convert(parser.NewClassExtractor().Extract(document))
+		for i := range mappings {
+			mappings[i].SemanticName = "div_1"
+		}
+
+		if got := NewHTMLGenerator().updateClassReferences(document, mappings, "a"); got != tt.want {
+			t.Errorf("%s\ngot:  %s\nwant: %s", tt.content, got, tt.want)
+		}
+	}
+}
@@ -8,81 +8,142 @@ import (
 type ClassExtractor struct{}
 
 type ExtractedClass struct {
-	Name     string
-	Category string
-	Context  string // Element context where it was found
+	Name      string
+	Utility   *ParsedUtility
+	Category  string
+	Context   string // Element context where it was found
+	Condition string // Condition guarding the class, empty if always applied
+}
+
+// ClassGroup holds the classes one element instance applies under one
+// condition, together with the class strings they were found in.
+type ClassGroup struct {
+	ElementID int
+	Element   string
+	Ancestors []int // IDs of the enclosing elements, innermost first
+	Path      string
+	Line      int
+	Column    int
+	Condition string
+	Classes   []ExtractedClass
+	Refs      []ClassRef
 }
 
 func NewClassExtractor() *ClassExtractor {
 	return &ClassExtractor{}
 }
 
-func (e *ClassExtractor) Extract(doc *Document) []ExtractedClass {
-	classMap := make(map[string]ExtractedClass)
+// Extract groups the classes of doc per element instance and condition, in
+// source order. A class is only deduplicated within its own group.
+func (e *ClassExtractor) Extract(doc *Document) []ClassGroup {
+	type groupKey struct {
+		element   int
+		condition string
+	}
+
+	var groups []ClassGroup
+	index := make(map[groupKey]int)
+	seen := make(map[groupKey]map[string]bool)
 
-	for _, ref := range doc.ClassRefs {
-		for _, class := range ref.Classes {
-			category := e.categorizeClass(class)
-			key := class + "_" + category
-
-			if _, exists := classMap[key]; !exists {
-				classMap[key] = ExtractedClass{
-					Name:     class,
-					Category: category,
-					Context:  ref.Element,
+	refs := make([]ClassRef, len(doc.ClassRefs))
+	copy(refs, doc.ClassRefs)
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].Start < refs[j].Start
+	})
+
+	for _, ref := range refs {
+		key := groupKey{element: ref.ElementID, condition: ref.Condition}
+		i, exists := index[key]
+		if !exists {
+			group := ClassGroup{
+				ElementID: ref.ElementID,
+				Element:   ref.Element,
+				Condition: ref.Condition,
+			}
+			if ref.ElementID < len(doc.Elements) {
+				element := doc.Elements[ref.ElementID]
+				group.Path = element.Path
+				group.Line = element.Line
+				group.Column = element.Column
+				for parent := element.Parent; parent >= 0 && parent < len(doc.Elements); parent = doc.Elements[parent].Parent {
+					group.Ancestors = append(group.Ancestors, parent)
 				}
 			}
+
+			i = len(groups)
+			index[key] = i
+			seen[key] = make(map[string]bool)
+			groups = append(groups, group)
 		}
-	}
 
-	// Convert map to slice and sort
-	classes := make([]ExtractedClass, 0, len(classMap))
-	for _, class := range classMap {
-		classes = append(classes, class)
-	}
+		groups[i].Refs = append(groups[i].Refs, ref)
+		for _, class := range ref.Classes {
+			if seen[key][class] {
+				continue
+			}
+			seen[key][class] = true
 
-	sort.Slice(classes, func(i, j int) bool {
-		return classes[i].Name < classes[j].Name
-	})
+			u, err := ParseUtility(class)
+			if err != nil {
+				continue
+			}
+
+			groups[i].Classes = append(groups[i].Classes, ExtractedClass{
+				Name:      class,
+				Utility:   u,
+				Category:  e.categorizeClass(u),
+				Context:   ref.Element,
+				Condition: ref.Condition,
+			})
+		}
+	}
 
-	return classes
+	return groups
 }
 
-func (e *ClassExtractor) categorizeClass(class string) string {
+func (e *ClassExtractor) categorizeClass(u *ParsedUtility) string {
+	root := u.Root
+
 	switch {
-	case strings.HasPrefix(class, "flex") || strings.HasPrefix(class, "grid") ||
-		strings.HasPrefix(class, "block") || strings.HasPrefix(class, "inline") ||
-		class == "hidden":
+	case strings.HasPrefix(root, "flex") || strings.HasPrefix(root, "grid") ||
+		strings.HasPrefix(root, "block") || strings.HasPrefix(root, "inline") ||
+		root == "hidden":
 		return "display"
 
-	case strings.HasPrefix(class, "items-") || strings.HasPrefix(class, "justify-") ||
-		strings.HasPrefix(class, "place-") || strings.HasPrefix(class, "content-"):
+	case root == "items" || strings.HasPrefix(root, "justify") ||
+		strings.HasPrefix(root, "place-") || root == "content" || root == "self":
 		return "alignment"
 
-	case strings.HasPrefix(class, "w-") || strings.HasPrefix(class, "h-") ||
-		strings.HasPrefix(class, "min-") || strings.HasPrefix(class, "max-"):
+	case root == "w" || root == "h" || root == "size" || root == "basis" ||
+		strings.HasPrefix(root, "min-") || strings.HasPrefix(root, "max-"):
 		return "sizing"
 
-	case strings.HasPrefix(class, "p-") || strings.HasPrefix(class, "m-") ||
-		strings.HasPrefix(class, "space-") || strings.HasPrefix(class, "gap-"):
+	case spacingRoots[root] || strings.HasPrefix(root, "space-") || strings.HasPrefix(root, "gap"):
 		return "spacing"
 
-	case strings.HasPrefix(class, "text-") || strings.HasPrefix(class, "font-") ||
-		strings.HasPrefix(class, "leading-") || strings.HasPrefix(class, "tracking-"):
+	case root == "text" || strings.HasPrefix(root, "font") ||
+		root == "leading" || root == "tracking":
 		return "typography"
 
-	case strings.HasPrefix(class, "bg-") || strings.HasPrefix(class, "border-") ||
-		strings.HasPrefix(class, "ring-") || strings.HasPrefix(class, "shadow-"):
+	case root == "bg" || strings.HasPrefix(root, "bg-") || strings.HasPrefix(root, "border") ||
+		root == "from" || root == "via" || root == "to" ||
+		strings.HasPrefix(root, "ring") || root == "shadow":
 		return "visual"
 
-	case strings.HasPrefix(class, "rounded") || strings.HasPrefix(class, "opacity-") ||
-		strings.HasPrefix(class, "scale-") || strings.HasPrefix(class, "rotate-"):
+	case strings.HasPrefix(root, "rounded") || root == "opacity" ||
+		strings.HasPrefix(root, "scale") || strings.HasPrefix(root, "rotate"):
 		return "effects"
 
-	case strings.Contains(class, ":"):
+	case len(u.Variants) > 0:
 		return "responsive"
 
 	default:
 		return "utility"
 	}
 }
+
+// Padding and margin roots
+var spacingRoots = setOf(
+	"p", "px", "py", "ps", "pe", "pt", "pr", "pb", "pl",
+	"m", "mx", "my", "ms", "me", "mt", "mr", "mb", "ml",
+)
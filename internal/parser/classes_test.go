@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExtractGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // "ElementID element path line:column [condition] ancestors: classes"
+	}{
+		{
+			name:    "one group per element instance",
+			content: "<div class=\"p-4 m-2 p-4\">\n  <span class=\"flex\"></span>\n  <span class=\"flex\"></span>\n</div>",
+			want: []string{
+				`0 div div[1] 1:1 [] []: p-4 m-2`,
+				`1 span div[1] > span[1] 2:3 [] [0]: flex`,
+				`2 span div[1] > span[2] 3:3 [] [0]: flex`,
+			},
+		},
+		{
+			name:    "ancestors innermost first",
+			content: "<main>\n<section class=\"p-1\">\n<div>\n<p class=\"m-1\"></p>\n</div>\n</section>\n</main>",
+			want: []string{
+				`1 section main[1] > section[1] 2:1 [] [0]: p-1`,
+				`3 p main[1] > section[1] > div[1] > p[1] 4:1 [] [2 1 0]: m-1`,
+			},
+		},
+		{
+			name:    "conditions split an element",
+			content: "const A = () => (\n  <ul className=\"p-1\">\n    <li className={cn(\"p-2\", on && \"bg-red-500\", on && \"p-2 m-1\", \"p-2\")} />\n  </ul>\n);",
+			want: []string{
+				`0 ul ul[1] 2:3 [] []: p-1`,
+				`1 li ul[1] > li[1] 3:5 [] [0]: p-2`,
+				`1 li ul[1] > li[1] 3:5 [on] [0]: bg-red-500 p-2 m-1`,
+			},
+		},
+		{
+			name:    "ternary branches",
+			content: "const A = () => <div className={open ? \"block\" : \"hidden\"} />;",
+			want: []string{
+				`0 div div[1] 1:17 [open] []: block`,
+				`0 div div[1] 1:17 [!open] []: hidden`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewHTMLParser().ParseContent(tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, group := range NewClassExtractor().Extract(doc) {
+				var names []string
+				for _, class := range group.Classes {
+					names = append(names, class.Name)
+				}
+				got = append(got, fmt.Sprintf("%d %s %s %d:%d [%s] %v: %s", group.ElementID, group.Element, group.Path,
+					group.Line, group.Column, group.Condition, group.Ancestors, strings.Join(names, " ")))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestExtractKeepsRefsPerGroup(t *testing.T) {
+	doc, err := NewHTMLParser().ParseContent(`const A = () => <li className={cn("p-2", on && "m-1", "p-2 flex")} />;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := NewClassExtractor().Extract(doc)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	for i, want := range []int{2, 1} {
+		if len(groups[i].Refs) != want {
+			t.Errorf("group %d [%s]: %d refs, want %d", i, groups[i].Condition, len(groups[i].Refs), want)
+		}
+	}
+	for _, class := range groups[0].Classes {
+		if class.Context != "li" || class.Utility == nil {
+			t.Errorf("class %s: context %q, utility %v", class.Name, class.Context, class.Utility)
+		}
+	}
+}
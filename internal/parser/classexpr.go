@@ -0,0 +1,388 @@
+package parser
+
+import (
+	"strings"
+)
+
+// exprToken is one lexical token of a class attribute expression. Offsets are
+// absolute positions in the document.
+type exprToken struct {
+	kind  byte // 's' string, '`' template, 'i' identifier, 'p' punctuation, 'o' other
+	text  string
+	start int
+	end   int
+}
+
+// Multi-character operators, longest first
+var exprOperators = []string{
+	"===", "!==", "...", "&&", "||", "??", "?.", "==", "!=", "=>", "<=", ">=",
+}
+
+// scanClassExpression records the static class strings of a class attribute
+// expression such as cn("flex", isActive && "bg-blue-500") or `p-4 ${x}`.
+func (t *tokenizer) scanClassExpression(attr ClassRef, start, end int) {
+	tokens := t.lexExpression(start, end)
+	t.classValue(attr, tokens, "")
+}
+
+func (t *tokenizer) lexExpression(start, end int) []exprToken {
+	saved := t.pos
+	defer func() { t.pos = saved }()
+
+	var tokens []exprToken
+	t.pos = start
+	for t.pos < end {
+		c := t.src[t.pos]
+		tokStart := t.pos
+
+		switch {
+		case isSpace(c):
+			t.pos++
+			continue
+		case c == '/' && t.peek(1) == '/':
+			t.skipLineComment()
+			continue
+		case c == '/' && t.peek(1) == '*':
+			t.skipBlockComment()
+			continue
+		case c == '\'' || c == '"':
+			t.skipString(c)
+			tokens = append(tokens, exprToken{kind: 's', start: tokStart, end: t.pos})
+		case c == '`':
+			t.skipTemplate()
+			tokens = append(tokens, exprToken{kind: '`', start: tokStart, end: t.pos})
+		case isIdentStart(c):
+			t.readIdent()
+			tokens = append(tokens, exprToken{kind: 'i', start: tokStart, end: t.pos})
+		case isDigit(c):
+			for t.pos < end && (isIdentChar(t.src[t.pos]) || t.src[t.pos] == '.') {
+				t.pos++
+			}
+			tokens = append(tokens, exprToken{kind: 'o', start: tokStart, end: t.pos})
+		default:
+			width := 1
+			for _, op := range exprOperators {
+				if strings.HasPrefix(t.src[t.pos:end], op) {
+					width = len(op)
+					break
+				}
+			}
+			t.pos += width
+			tokens = append(tokens, exprToken{kind: 'p', start: tokStart, end: t.pos})
+		}
+
+		last := &tokens[len(tokens)-1]
+		last.text = t.src[last.start:last.end]
+	}
+
+	return tokens
+}
+
+// classValue walks an expression in a position whose value ends up in the
+// class list, recording static strings under the condition that guards them.
+func (t *tokenizer) classValue(attr ClassRef, tokens []exprToken, condition string) {
+	if len(tokens) == 0 {
+		return
+	}
+
+	// cond ? a : b
+	if q := indexTop(tokens, "?"); q > 0 {
+		colon := matchingColon(tokens, q+1)
+		if colon < 0 {
+			return
+		}
+		test := t.tokenText(tokens[:q])
+		t.classValue(attr, tokens[q+1:colon], joinConditions(condition, test))
+		t.classValue(attr, tokens[colon+1:], joinConditions(condition, negateCondition(test)))
+		return
+	}
+
+	// a || b and a ?? b pick a value at runtime we cannot know statically
+	if indexTop(tokens, "||") >= 0 || indexTop(tokens, "??") >= 0 {
+		return
+	}
+
+	// cond && "classes"
+	if parts := splitTop(tokens, "&&"); len(parts) > 1 {
+		last := parts[len(parts)-1]
+		test := t.tokenText(tokens[:len(tokens)-len(last)-1])
+		t.classValue(attr, last, joinConditions(condition, test))
+		return
+	}
+
+	first := tokens[0]
+	switch {
+	case len(tokens) == 1 && first.kind == 's':
+		ref := attr
+		ref.Kind = StringRef
+		ref.Condition = condition
+		ref.Quote = first.text[0]
+		ref.Start = first.start + 1
+		ref.End = first.end - 1
+		if !strings.ContainsRune(t.src[ref.Start:ref.End], '\\') {
+			t.addClassRef(ref)
+		}
+	case len(tokens) == 1 && first.kind == '`':
+		t.templateChunks(attr, first, condition)
+	case isWrapped(tokens, "(", ")"):
+		t.classValue(attr, tokens[1:len(tokens)-1], condition)
+	case isWrapped(tokens, "[", "]"):
+		for _, item := range splitTop(tokens[1:len(tokens)-1], ",") {
+			t.classValue(attr, item, condition)
+		}
+	case isWrapped(tokens, "{", "}"):
+		t.objectKeys(attr, tokens[1:len(tokens)-1], condition)
+	case first.kind == 'i':
+		t.helperCall(attr, tokens, condition)
+	}
+}
+
+// helperCall records the arguments of a call to one of the configured class
+// helpers, such as cn(...) or utils.clsx(...).
+func (t *tokenizer) helperCall(attr ClassRef, tokens []exprToken, condition string) {
+	open := 0
+	for open < len(tokens) && (tokens[open].kind == 'i' || tokens[open].text == ".") {
+		open++
+	}
+	if open == len(tokens) || tokens[open].text != "(" || tokens[open-1].kind != 'i' {
+		return
+	}
+	if matchingClose(tokens, open) != len(tokens)-1 {
+		return
+	}
+
+	name := tokens[open-1].text
+	if !t.parser.classHelpers[name] {
+		return
+	}
+
+	attr.Helper = name
+	for _, arg := range splitTop(tokens[open+1:len(tokens)-1], ",") {
+		t.classValue(attr, arg, condition)
+	}
+}
+
+// objectKeys records the keys of an object literal such as
+// {"bg-red-500": hasError, hidden: !open}, each guarded by its value.
+func (t *tokenizer) objectKeys(attr ClassRef, tokens []exprToken, condition string) {
+	for _, entry := range splitTop(tokens, ",") {
+		colon := indexTop(entry, ":")
+		if colon != 1 || len(entry) < 3 {
+			continue
+		}
+
+		key := entry[0]
+		ref := attr
+		ref.Kind = ObjectKeyRef
+		ref.Condition = joinConditions(condition, t.tokenText(entry[2:]))
+
+		switch key.kind {
+		case 's':
+			ref.Quote = key.text[0]
+			ref.Start = key.start + 1
+			ref.End = key.end - 1
+		case 'i':
+			ref.Start = key.start
+			ref.End = key.end
+		default:
+			continue
+		}
+		t.addClassRef(ref)
+	}
+}
+
+// templateChunks records the static text between the substitutions of a
+// template literal. Classes touching a substitution, like the text- in
+// `text-${size}`, are only partially known and are left alone.
+func (t *tokenizer) templateChunks(attr ClassRef, token exprToken, condition string) {
+	saved := t.pos
+	defer func() { t.pos = saved }()
+
+	chunkStart := token.start + 1
+	t.pos = chunkStart
+	for t.pos < token.end {
+		c := t.src[t.pos]
+		switch {
+		case c == '\\':
+			t.pos += 2
+			continue
+		case c == '`' || (c == '$' && t.peek(1) == '{'):
+			t.addTemplateChunk(attr, chunkStart, t.pos, token, condition)
+			if c == '`' {
+				return
+			}
+			t.pos += 2
+			t.scanScript()
+			t.skipByte('}')
+			chunkStart = t.pos
+			continue
+		}
+		t.pos++
+	}
+}
+
+func (t *tokenizer) addTemplateChunk(attr ClassRef, start, end int, token exprToken, condition string) {
+	chunk := t.src[start:end]
+	if strings.ContainsRune(chunk, '\\') {
+		return
+	}
+
+	// Drop a leading class glued to the previous substitution and a trailing
+	// class glued to the next one
+	afterSubstitution := start > token.start+1
+	beforeSubstitution := end < token.end-1
+	if afterSubstitution && chunk != "" && !isSpace(chunk[0]) {
+		skip := strings.IndexFunc(chunk, func(r rune) bool { return r < 128 && isSpace(byte(r)) })
+		if skip < 0 {
+			return
+		}
+		start += skip
+	}
+	chunk = t.src[start:end]
+	if beforeSubstitution && chunk != "" && !isSpace(chunk[len(chunk)-1]) {
+		cut := strings.LastIndexFunc(chunk, func(r rune) bool { return r < 128 && isSpace(byte(r)) })
+		if cut < 0 {
+			return
+		}
+		end = start + cut
+	}
+
+	// Trim the chunk down to the classes themselves
+	chunk = t.src[start:end]
+	trimmed := strings.TrimSpace(chunk)
+	if trimmed == "" {
+		return
+	}
+	start += strings.Index(chunk, trimmed)
+
+	ref := attr
+	ref.Kind = TemplateRef
+	ref.Condition = condition
+	ref.Quote = '`'
+	ref.Start = start
+	ref.End = start + len(trimmed)
+	t.addClassRef(ref)
+}
+
+// tokenText returns the source text spanned by tokens with whitespace collapsed
+func (t *tokenizer) tokenText(tokens []exprToken) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return strings.Join(strings.Fields(t.src[tokens[0].start:tokens[len(tokens)-1].end]), " ")
+}
+
+func joinConditions(outer, inner string) string {
+	if outer == "" {
+		return inner
+	}
+	if inner == "" {
+		return outer
+	}
+	return outer + " && " + inner
+}
+
+func negateCondition(condition string) string {
+	simple := true
+	for i := 0; i < len(condition); i++ {
+		if !isIdentChar(condition[i]) && condition[i] != '.' {
+			simple = false
+			break
+		}
+	}
+
+	if simple {
+		return "!" + condition
+	}
+	return "!(" + condition + ")"
+}
+
+// indexTop returns the index of the first top-level token with the given text
+func indexTop(tokens []exprToken, text string) int {
+	depth := 0
+	for i, tok := range tokens {
+		if tok.kind != 'p' {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		default:
+			if depth == 0 && tok.text == text {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTop splits tokens at every top-level occurrence of sep
+func splitTop(tokens []exprToken, sep string) [][]exprToken {
+	var parts [][]exprToken
+	for {
+		i := indexTop(tokens, sep)
+		if i < 0 {
+			break
+		}
+		parts = append(parts, tokens[:i])
+		tokens = tokens[i+1:]
+	}
+	return append(parts, tokens)
+}
+
+// matchingColon finds the `:` that pairs with a ternary `?`, skipping nested
+// ternaries in the consequent.
+func matchingColon(tokens []exprToken, from int) int {
+	depth, nested := 0, 0
+	for i := from; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.kind != 'p' {
+			continue
+		}
+		switch tok.text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case "?":
+			if depth == 0 {
+				nested++
+			}
+		case ":":
+			if depth == 0 {
+				if nested == 0 {
+					return i
+				}
+				nested--
+			}
+		}
+	}
+	return -1
+}
+
+// matchingClose returns the index of the bracket closing tokens[open]
+func matchingClose(tokens []exprToken, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].kind != 'p' {
+			continue
+		}
+		switch tokens[i].text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isWrapped(tokens []exprToken, open, close string) bool {
+	return len(tokens) >= 2 && tokens[0].text == open && tokens[len(tokens)-1].text == close &&
+		matchingClose(tokens, 0) == len(tokens)-1
+}
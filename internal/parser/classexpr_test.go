@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassExpressions(t *testing.T) {
+	type ref struct {
+		classes   string
+		kind      RefKind
+		condition string
+		helper    string
+	}
+	tests := []struct {
+		name       string
+		expression string
+		helpers    []string
+		want       []ref
+	}{
+		{
+			name:       "ternary",
+			expression: `x ? "p-1 flex" : "m-1"`,
+			want:       []ref{{"p-1 flex", StringRef, "x", ""}, {"m-1", StringRef, "!x", ""}},
+		},
+		{
+			name:       "helper arguments",
+			expression: `cn("flex gap-2", isActive && "bg-blue-500", className)`,
+			want:       []ref{{"flex gap-2", StringRef, "", "cn"}, {"bg-blue-500", StringRef, "isActive", "cn"}},
+		},
+		{
+			name:       "nested conditions",
+			expression: `clsx("p-4", a && (b ? "m-1" : "m-2"), !c && "gap-1")`,
+			want: []ref{
+				{"p-4", StringRef, "", "clsx"},
+				{"m-1", StringRef, "a && b", "clsx"},
+				{"m-2", StringRef, "a && !b", "clsx"},
+				{"gap-1", StringRef, "!c", "clsx"},
+			},
+		},
+		{
+			name:       "object keys",
+			expression: `twMerge({ "p-4": a, "text-lg": big })`,
+			want:       []ref{{"p-4", ObjectKeyRef, "a", "twMerge"}, {"text-lg", ObjectKeyRef, "big", "twMerge"}},
+		},
+		{
+			name:       "array argument",
+			expression: `cn(["p-1", "p-2"])`,
+			want:       []ref{{"p-1", StringRef, "", "cn"}, {"p-2", StringRef, "", "cn"}},
+		},
+		{
+			name:       "template literal",
+			expression: "`p-4 ${x} m-2`",
+			want:       []ref{{"p-4", TemplateRef, "", ""}, {"m-2", TemplateRef, "", ""}},
+		},
+		{
+			name:       "unknown function",
+			expression: `foo("p-4")`,
+		},
+		{
+			name:       "configured helper",
+			expression: `tw("p-4")`,
+			helpers:    []string{"tw"},
+			want:       []ref{{"p-4", StringRef, "", "tw"}},
+		},
+	}
+
+	for _, tt := range tests {
+		p := NewHTMLParser()
+		if tt.helpers != nil {
+			p.SetClassHelpers(tt.helpers)
+		}
+		doc, err := p.ParseContent("const A = () => <div className={" + tt.expression + "} />;")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(doc.ClassRefs) != len(tt.want) {
+			t.Errorf("%s: got %d class refs, want %d", tt.name, len(doc.ClassRefs), len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			r := doc.ClassRefs[i]
+			got := ref{strings.Join(r.Classes, " "), r.Kind, r.Condition, r.Helper}
+			if got != want {
+				t.Errorf("%s: ref %d = %+v, want %+v", tt.name, i, got, want)
+			}
+		}
+	}
+}
@@ -2,34 +2,83 @@ package parser
 
 import (
 	"io/ioutil"
-	"regexp"
-	"strings"
 )
 
 type HTMLParser struct {
-	classRegex *regexp.Regexp
+	classHelpers map[string]bool
+	mapped       func(u *ParsedUtility) bool // Whether a utility converts, nil to go by its root alone
 }
 
+// Functions whose arguments are treated as class lists
+var defaultClassHelpers = []string{"clsx", "cn", "classnames", "classNames", "cx", "twMerge", "twJoin"}
+
 type Document struct {
 	Content   string
 	ClassRefs []ClassRef
+	Elements  []Element
+}
+
+// Element is one element instance in the document, in source order
+type Element struct {
+	ID     int
+	Name   string
+	Parent int    // ID of the enclosing element, -1 at the top level
+	Path   string // DOM path such as "div[1] > header[1] > h1[1]"
+	Line   int
+	Column int
 }
 
+// RefKind describes where a class string sits in the source, which decides
+// how it is rewritten.
+type RefKind int
+
+const (
+	AttributeRef RefKind = iota // The whole attribute value, class="..." or className={"..."}
+	StringRef                   // A string literal inside an expression
+	TemplateRef                 // The static text of a template literal
+	ObjectKeyRef                // An object literal key, {"bg-red-500": hasError}
+)
+
+// ClassRef is one static class string found in the source. Start and End
+// delimit the class text itself; AttrStart and ValueEnd delimit the whole
+// attribute so it can be rewritten in place.
 type ClassRef struct {
-	Classes []string
-	Start   int
-	End     int
-	Element string
+	Classes    []string
+	Start      int
+	End        int
+	Element    string
+	ElementID  int    // Index into Document.Elements
+	Attr       string // "class" or "className"
+	AttrStart  int
+	ValueStart int  // Start of the attribute value, including quotes or braces
+	ValueEnd   int  // End of the attribute value, including quotes or braces
+	Quote      byte // Quote around the class string, 0 when unquoted
+	Kind       RefKind
+	Condition  string // JavaScript condition guarding the classes, empty if always applied
+	Helper     string // Class helper the string was passed to, e.g. "cn"
 }
 
 func NewHTMLParser() *HTMLParser {
-	// Regex to match class attributes in HTML/JSX
-	classRegex := regexp.MustCompile(`(class|className)=["']([^"']+)["']`)
-	return &HTMLParser{
-		classRegex: classRegex,
+	p := &HTMLParser{}
+	p.SetClassHelpers(defaultClassHelpers)
+	return p
+}
+
+// SetClassHelpers replaces the list of functions, like cn or clsx, whose
+// arguments are scanned for classes.
+func (p *HTMLParser) SetClassHelpers(names []string) {
+	p.classHelpers = make(map[string]bool)
+	for _, name := range names {
+		p.classHelpers[name] = true
 	}
 }
 
+// SetUtilityMapper sets the check that a utility has a conversion, so custom
+// classes sharing a Tailwind root, like col-md-6, stay in the markup.
+func (p *HTMLParser) SetUtilityMapper(mapped func(u *ParsedUtility) bool) {
+	p.mapped = mapped
+}
+
 func (p *HTMLParser) ParseFile(filepath string) (*Document, error) {
 	content, err := ioutil.ReadFile(filepath)
 	if err != nil {
@@ -45,79 +94,22 @@ func (p *HTMLParser) ParseContent(content string) (*Document, error) {
 		ClassRefs: []ClassRef{},
 	}
 
-	// Find all class attributes
-	matches := p.classRegex.FindAllStringSubmatchIndex(content, -1)
-
-	for _, match := range matches {
-		if len(match) >= 6 {
-			start := match[4] // Start of class values
-			end := match[5]   // End of class values
-			classValues := content[start:end]
-
-			// Split classes by whitespace
-			classes := strings.Fields(classValues)
-
-			// Filter for Tailwind classes (simple heuristic)
-			tailwindClasses := []string{}
-			for _, class := range classes {
-				if p.isTailwindClass(class) {
-					tailwindClasses = append(tailwindClasses, class)
-				}
-			}
-
-			if len(tailwindClasses) > 0 {
-				// Extract element info for context
-				elementStart := p.findElementStart(content, match[0])
-				element := p.extractElementName(content, elementStart)
-
-				doc.ClassRefs = append(doc.ClassRefs, ClassRef{
-					Classes: tailwindClasses,
-					Start:   start,
-					End:     end,
-					Element: element,
-				})
-			}
-		}
-	}
+	// Walk the markup and record every static class string
+	newTokenizer(p, doc).run()
 
 	return doc, nil
 }
 
 func (p *HTMLParser) isTailwindClass(class string) bool {
-	// Common Tailwind prefixes and patterns
-	tailwindPrefixes := []string{
-		"flex", "grid", "block", "inline", "hidden",
-		"text-", "bg-", "border-", "p-", "m-", "w-", "h-",
-		"items-", "justify-", "gap-", "space-", "rounded",
-		"font-", "leading-", "tracking-", "opacity-",
-		"hover:", "focus:", "active:", "disabled:",
-		"sm:", "md:", "lg:", "xl:", "2xl:",
-	}
-
-	for _, prefix := range tailwindPrefixes {
-		if strings.HasPrefix(class, prefix) || class == strings.TrimSuffix(prefix, "-") {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (p *HTMLParser) findElementStart(content string, classPos int) int {
-	// Look backwards for the start of the element
-	for i := classPos; i >= 0; i-- {
-		if content[i] == '<' {
-			return i
-		}
+	// Utilities with a conversion count as Tailwind, and without a mapper
+	// anything with a variant or a known utility root; plain custom classes
+	// stay in the markup
+	u, err := ParseUtility(class)
+	if err != nil {
+		return false
 	}
-	return 0
-}
-
-func (p *HTMLParser) extractElementName(content string, start int) string {
-	// Extract element name from opening tag
-	tagMatch := regexp.MustCompile(`<(\w+(?:\.\w+)*)`).FindStringSubmatch(content[start : start+50])
-	if len(tagMatch) > 1 {
-		return tagMatch[1]
+	if p.mapped != nil {
+		return u.IsKnown(p.mapped)
 	}
-	return "element"
+	return u.IsKnown(nil) || len(u.Variants) > 0
 }
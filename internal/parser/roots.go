@@ -0,0 +1,96 @@
+package parser
+
+// functionalRoots are the utility roots that take a value, as in bg-blue-500
+// or mt-4. ParseUtility splits a class at the longest matching root, and the
+// converter's mappings are registered against the same names.
+var functionalRoots = setOf(
+	// Layout
+	"aspect", "columns", "break-after", "break-before", "break-inside",
+	"box-decoration", "box", "float", "clear", "isolation", "object",
+	"overflow", "overflow-x", "overflow-y", "overscroll", "overscroll-x",
+	"overscroll-y", "inset", "inset-x", "inset-y", "start", "end", "top",
+	"right", "bottom", "left", "z", "@container",
+
+	// Flexbox and grid
+	"basis", "flex", "grow", "shrink", "order", "grid-cols", "col",
+	"col-span", "col-start", "col-end", "grid-rows", "row", "row-span",
+	"row-start", "row-end", "grid-flow", "auto-cols", "auto-rows", "gap",
+	"gap-x", "gap-y", "justify", "justify-items", "justify-self", "content",
+	"items", "self", "place-content", "place-items", "place-self",
+
+	// Spacing
+	"p", "px", "py", "ps", "pe", "pt", "pr", "pb", "pl", "m", "mx", "my",
+	"ms", "me", "mt", "mr", "mb", "ml", "space-x", "space-y",
+
+	// Sizing
+	"size", "w", "min-w", "max-w", "h", "min-h", "max-h",
+
+	// Typography
+	"font", "font-stretch", "text", "leading", "tracking", "line-clamp",
+	"list", "list-image", "decoration", "underline-offset", "indent",
+	"align", "whitespace", "wrap", "break", "hyphens",
+
+	// Backgrounds and gradients
+	"bg", "bg-linear", "bg-gradient", "bg-radial", "bg-conic", "from", "via", "to",
+
+	// Borders, outlines and rings
+	"rounded", "rounded-s", "rounded-e", "rounded-t", "rounded-r",
+	"rounded-b", "rounded-l", "rounded-ss", "rounded-se", "rounded-ee",
+	"rounded-es", "rounded-tl", "rounded-tr", "rounded-br", "rounded-bl",
+	"border", "border-x", "border-y", "border-s", "border-e", "border-t",
+	"border-r", "border-b", "border-l", "divide", "divide-x", "divide-y",
+	"outline", "outline-offset", "ring", "ring-offset", "inset-ring",
+
+	// Effects and filters
+	"filter", "backdrop-filter", "shadow", "inset-shadow", "text-shadow", "opacity", "mix-blend",
+	"bg-blend", "blur", "brightness", "contrast", "drop-shadow",
+	"grayscale", "hue-rotate", "invert", "saturate", "sepia",
+	"backdrop-blur", "backdrop-brightness", "backdrop-contrast",
+	"backdrop-grayscale", "backdrop-hue-rotate", "backdrop-invert",
+	"backdrop-opacity", "backdrop-saturate", "backdrop-sepia",
+
+	// Transitions and animation
+	"transition", "duration", "ease", "delay", "animate",
+
+	// Transforms
+	"scale", "scale-x", "scale-y", "scale-z", "rotate", "rotate-x",
+	"rotate-y", "rotate-z", "translate", "translate-x", "translate-y",
+	"translate-z", "skew", "skew-x", "skew-y", "origin", "perspective",
+	"perspective-origin", "transform", "backface",
+
+	// Interactivity
+	"accent", "appearance", "caret", "cursor", "pointer-events", "resize",
+	"scroll", "scroll-m", "scroll-mx", "scroll-my", "scroll-ms",
+	"scroll-me", "scroll-mt", "scroll-mr", "scroll-mb", "scroll-ml",
+	"scroll-p", "scroll-px", "scroll-py", "scroll-ps", "scroll-pe",
+	"scroll-pt", "scroll-pr", "scroll-pb", "scroll-pl", "snap", "touch",
+	"select", "will-change",
+
+	// SVG, tables and accessibility
+	"fill", "stroke", "border-spacing", "border-spacing-x",
+	"border-spacing-y", "table", "caption", "forced-color-adjust",
+
+	// Markers for group-* and peer-* variants
+	"group", "peer",
+)
+
+// staticUtilities are the keyword utilities that are not a root plus value
+var staticUtilities = setOf(
+	"container", "grid", "block", "inline", "inline-block", "inline-flex",
+	"inline-grid", "inline-table", "hidden", "contents", "flow-root",
+	"list-item", "static", "relative", "absolute", "fixed", "sticky",
+	"visible", "invisible", "collapse", "isolate", "sr-only", "not-sr-only",
+	"truncate", "italic", "not-italic", "underline", "overline",
+	"line-through", "no-underline", "uppercase", "lowercase", "capitalize",
+	"normal-case", "antialiased", "subpixel-antialiased", "ordinal",
+	"slashed-zero", "lining-nums", "oldstyle-nums", "proportional-nums",
+	"tabular-nums", "diagonal-fractions", "stacked-fractions", "normal-nums",
+)
+
+func setOf(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
@@ -0,0 +1,461 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Theme holds the design tokens declared in the @theme blocks of a Tailwind
+// v4 stylesheet, e.g. --color-brand-500 or --breakpoint-3xl. Later
+// declarations override earlier ones, and "--color-*: initial" removes a
+// whole namespace, so a user theme can be parsed on top of the defaults.
+// The stylesheet's @custom-variant declarations and the @keyframes of its
+// @theme blocks are kept alongside.
+type Theme struct {
+	tokens    map[string]ThemeToken
+	order     []string
+	variants  []CustomVariant
+	keyframes map[string]Keyframes
+}
+
+type ThemeToken struct {
+	Name   string // Variable name, e.g. "--color-brand-500"
+	Value  string
+	Inline bool // Declared in @theme inline, so always used by value
+}
+
+// CustomVariant is a variant declared with @custom-variant, e.g.
+// "@custom-variant dark (&:where(.dark, .dark *));"
+type CustomVariant struct {
+	Name  string
+	Rules []string // Selectors, with "&" for the element, and at-rules, outermost first
+}
+
+// Keyframes is an @keyframes rule declared in @theme, like the spin of
+// --animate-spin: spin 1s linear infinite
+type Keyframes struct {
+	Name  string
+	Steps []KeyframeStep
+}
+
+type KeyframeStep struct {
+	Selector     string // "to", "50%" or "0%, 100%"
+	Declarations []Declaration
+}
+
+type Declaration struct {
+	Property string
+	Value    string
+}
+
+func NewTheme() *Theme {
+	return &Theme{tokens: make(map[string]ThemeToken), keyframes: make(map[string]Keyframes)}
+}
+
+func (t *Theme) ParseFile(filepath string) error {
+	content, err := ioutil.ReadFile(filepath)
+	if err != nil {
+		return err
+	}
+	if err := t.Parse(string(content)); err != nil {
+		return fmt.Errorf("%s: %v", filepath, err)
+	}
+	return nil
+}
+
+// Parse reads the @theme blocks and @custom-variant declarations of a
+// stylesheet. Everything else, like @import or regular rules, is ignored.
+func (t *Theme) Parse(css string) error {
+	css = stripCSSComments(css)
+
+	for _, statement := range splitCSSStatements(css) {
+		if statement.body != nil && statement.end < 0 {
+			return fmt.Errorf("unterminated block after %q", statement.prelude)
+		}
+
+		name, params := atRuleName(statement.prelude)
+		if name == "@custom-variant" {
+			if err := t.parseCustomVariant(params, statement.body); err != nil {
+				return err
+			}
+			continue
+		}
+		if name != "@theme" || statement.body == nil {
+			continue
+		}
+		inline := false
+		for _, option := range strings.Fields(params) {
+			if option == "inline" {
+				inline = true
+			}
+		}
+		if err := t.parseBlock(*statement.body, inline); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Theme) parseBlock(body string, inline bool) error {
+	for _, statement := range splitCSSStatements(body) {
+		if statement.body != nil {
+			if statement.end < 0 {
+				return fmt.Errorf("unterminated block after %q in @theme", statement.prelude)
+			}
+			if name, params := atRuleName(statement.prelude); name == "@keyframes" && params != "" {
+				t.keyframes[params] = parseKeyframes(params, *statement.body)
+			}
+			continue
+		}
+
+		colon := strings.IndexByte(statement.prelude, ':')
+		if colon < 0 {
+			continue
+		}
+		name := strings.TrimSpace(statement.prelude[:colon])
+		value := strings.TrimSpace(statement.prelude[colon+1:])
+		if !strings.HasPrefix(name, "--") || value == "" {
+			continue
+		}
+
+		switch {
+		case value == "initial" && name == "--*":
+			t.Reset("")
+		case value == "initial" && strings.HasSuffix(name, "-*"):
+			t.Reset(strings.TrimSuffix(strings.TrimPrefix(name, "--"), "-*"))
+		case value == "initial":
+			t.remove(name)
+		default:
+			t.Set(name, value, inline)
+		}
+	}
+	return nil
+}
+
+// parseCustomVariant reads the shorthand "name (rule)" form, or the block
+// form whose nested rules end in @slot:
+//
+//	@custom-variant dark {
+//	  &:where(.dark, .dark *) {
+//	    @slot;
+//	  }
+//	}
+func (t *Theme) parseCustomVariant(params string, body *string) error {
+	fields := strings.Fields(params)
+	if len(fields) == 0 {
+		return fmt.Errorf("@custom-variant without a name")
+	}
+	variant := CustomVariant{Name: fields[0]}
+
+	if body == nil {
+		rule := strings.TrimSpace(strings.TrimPrefix(params, fields[0]))
+		if !strings.HasPrefix(rule, "(") || !strings.HasSuffix(rule, ")") {
+			return fmt.Errorf("@custom-variant %s: expected a (selector) or (@rule)", variant.Name)
+		}
+		variant.Rules = []string{strings.TrimSpace(rule[1 : len(rule)-1])}
+	} else {
+		rules, ok := slotRules(*body)
+		if !ok {
+			return fmt.Errorf("@custom-variant %s: expected nested rules ending in @slot", variant.Name)
+		}
+		variant.Rules = rules
+	}
+
+	for i, existing := range t.variants {
+		if existing.Name == variant.Name {
+			t.variants[i] = variant
+			return nil
+		}
+	}
+	t.variants = append(t.variants, variant)
+	return nil
+}
+
+// slotRules returns the preludes of the blocks leading to @slot
+func slotRules(body string) ([]string, bool) {
+	for _, statement := range splitCSSStatements(body) {
+		if statement.body == nil {
+			if statement.prelude == "@slot" {
+				return nil, true
+			}
+			continue
+		}
+		if statement.end < 0 {
+			return nil, false
+		}
+		if inner, ok := slotRules(*statement.body); ok {
+			return append([]string{statement.prelude}, inner...), true
+		}
+	}
+	return nil, false
+}
+
+// parseKeyframes reads the steps of an @keyframes body
+func parseKeyframes(name, body string) Keyframes {
+	keyframes := Keyframes{Name: name}
+	for _, statement := range splitCSSStatements(body) {
+		if statement.body == nil || statement.end < 0 {
+			continue
+		}
+		step := KeyframeStep{Selector: strings.Join(strings.Fields(statement.prelude), " ")}
+		for _, declaration := range splitCSSStatements(*statement.body) {
+			colon := strings.IndexByte(declaration.prelude, ':')
+			if declaration.body != nil || colon < 0 {
+				continue
+			}
+			step.Declarations = append(step.Declarations, Declaration{
+				Property: strings.TrimSpace(declaration.prelude[:colon]),
+				Value:    strings.TrimSpace(declaration.prelude[colon+1:]),
+			})
+		}
+		keyframes.Steps = append(keyframes.Steps, step)
+	}
+	return keyframes
+}
+
+// Keyframes returns the @keyframes rule of an animation name
+func (t *Theme) Keyframes(name string) (Keyframes, bool) {
+	keyframes, exists := t.keyframes[name]
+	return keyframes, exists
+}
+
+// CustomVariants returns the @custom-variant declarations in the order read
+func (t *Theme) CustomVariants() []CustomVariant {
+	return t.variants
+}
+
+// Set declares a token, replacing any earlier value
+func (t *Theme) Set(name, value string, inline bool) {
+	if _, exists := t.tokens[name]; !exists {
+		t.order = append(t.order, name)
+	}
+	t.tokens[name] = ThemeToken{Name: name, Value: value, Inline: inline}
+}
+
+// Reset removes every token of a namespace, e.g. "color" for --color-*, or
+// all tokens and keyframes when namespace is empty
+func (t *Theme) Reset(namespace string) {
+	if namespace == "" {
+		t.keyframes = make(map[string]Keyframes)
+	}
+	for _, name := range append([]string(nil), t.order...) {
+		if namespace == "" || inNamespace(name, namespace) {
+			t.remove(name)
+		}
+	}
+}
+
+// themeNamespaces are the token namespaces Tailwind's utilities read. Some
+// start with another, as font-weight does with font.
+var themeNamespaces = []string{
+	"color", "font", "font-weight", "text", "text-shadow", "tracking",
+	"leading", "breakpoint", "container", "spacing", "radius", "shadow",
+	"inset-shadow", "drop-shadow", "blur", "perspective", "aspect", "ease",
+	"animate", "default",
+}
+
+// inNamespace reports whether a token belongs to namespace and not to a
+// longer namespace it starts, so that --font-*: initial keeps
+// --font-weight-bold and --text-*: initial keeps --text-shadow-sm
+func inNamespace(name, namespace string) bool {
+	if name != "--"+namespace && !strings.HasPrefix(name, "--"+namespace+"-") {
+		return false
+	}
+	for _, owner := range themeNamespaces {
+		if !strings.HasPrefix(owner, namespace+"-") {
+			continue
+		}
+		if name == "--"+owner || strings.HasPrefix(name, "--"+owner+"-") {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Theme) remove(name string) {
+	if _, exists := t.tokens[name]; !exists {
+		return
+	}
+	delete(t.tokens, name)
+	for i, existing := range t.order {
+		if existing == name {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (t *Theme) Token(name string) (ThemeToken, bool) {
+	token, exists := t.tokens[name]
+	return token, exists
+}
+
+// Value returns the value of a token, following references to other tokens
+// like var(--color-blue-500)
+func (t *Theme) Value(name string) (string, bool) {
+	token, exists := t.tokens[name]
+	if !exists {
+		return "", false
+	}
+
+	value := token.Value
+	for depth := 0; depth < 10; depth++ {
+		if !strings.HasPrefix(value, "var(") || !strings.HasSuffix(value, ")") {
+			break
+		}
+		ref := strings.TrimSpace(value[4 : len(value)-1])
+		if comma := strings.IndexByte(ref, ','); comma >= 0 {
+			ref = strings.TrimSpace(ref[:comma])
+		}
+		referenced, exists := t.tokens[ref]
+		if !exists {
+			break
+		}
+		value = referenced.Value
+	}
+	return value, true
+}
+
+// Namespace returns the tokens of a namespace in declaration order, keyed by
+// the part after the namespace: "3xl" for --breakpoint-3xl. Sub-properties
+// such as --text-xs--line-height are left out.
+func (t *Theme) Namespace(namespace string) []ThemeToken {
+	prefix := "--" + namespace + "-"
+	var tokens []ThemeToken
+	for _, name := range t.order {
+		key := strings.TrimPrefix(name, prefix)
+		if key == name || key == "" || strings.Contains(key, "--") {
+			continue
+		}
+		token := t.tokens[name]
+		token.Name = key
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// cssStatement is a declaration or at-rule ending in ";", or a rule or
+// at-rule with a block. end is -1 for an unterminated block.
+type cssStatement struct {
+	prelude string
+	body    *string
+	end     int
+}
+
+// splitCSSStatements splits CSS into its top-level statements
+func splitCSSStatements(css string) []cssStatement {
+	var statements []cssStatement
+	start := 0
+	depth := 0
+	var quote byte
+
+	for i := 0; i < len(css); i++ {
+		c := css[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ';' && depth == 0:
+			if prelude := strings.TrimSpace(css[start:i]); prelude != "" {
+				statements = append(statements, cssStatement{prelude: prelude})
+			}
+			start = i + 1
+		case c == '{' && depth == 0:
+			prelude := strings.TrimSpace(css[start:i])
+			end := matchingBrace(css, i)
+			if end < 0 {
+				body := css[i+1:]
+				return append(statements, cssStatement{prelude: prelude, body: &body, end: -1})
+			}
+			body := css[i+1 : end]
+			statements = append(statements, cssStatement{prelude: prelude, body: &body, end: end})
+			i = end
+			start = end + 1
+		}
+	}
+
+	if prelude := strings.TrimSpace(css[start:]); prelude != "" {
+		statements = append(statements, cssStatement{prelude: prelude})
+	}
+	return statements
+}
+
+// matchingBrace returns the index of the brace closing css[open], or -1
+func matchingBrace(css string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(css); i++ {
+		c := css[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// atRuleName splits "@theme inline" into "@theme" and "inline"
+func atRuleName(prelude string) (string, string) {
+	if !strings.HasPrefix(prelude, "@") {
+		return "", prelude
+	}
+	end := strings.IndexAny(prelude, " \t\r\n(")
+	if end < 0 {
+		return prelude, ""
+	}
+	return prelude[:end], strings.TrimSpace(prelude[end:])
+}
+
+func stripCSSComments(css string) string {
+	var out strings.Builder
+	var quote byte
+	for i := 0; i < len(css); i++ {
+		c := css[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(css) {
+				out.WriteByte(c)
+				i++
+				c = css[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '/' && i+1 < len(css) && css[i+1] == '*':
+			end := strings.Index(css[i+2:], "*/")
+			if end < 0 {
+				return out.String()
+			}
+			i += end + 3
+			out.WriteByte(' ')
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
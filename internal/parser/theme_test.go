@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestThemeReset(t *testing.T) {
+	tests := []struct {
+		name    string
+		css     string
+		token   string
+		want    string
+		defined bool
+	}{
+		{
+			name:  "full reset removes defaults",
+			css:   "@theme { --*: initial; }",
+			token: "--color-blue-500",
+		},
+		{
+			name:    "full reset keeps later tokens",
+			css:     "@theme { --*: initial; --color-brand-500: #123456; }",
+			token:   "--color-brand-500",
+			want:    "#123456",
+			defined: true,
+		},
+		{
+			name:  "namespace reset",
+			css:   "@theme { --color-*: initial; }",
+			token: "--color-blue-500",
+		},
+		{
+			name:    "namespace reset keeps other namespaces",
+			css:     "@theme { --color-*: initial; }",
+			token:   "--text-lg",
+			want:    "1.125rem",
+			defined: true,
+		},
+		{
+			name:    "font reset keeps font weights",
+			css:     "@theme { --font-*: initial; }",
+			token:   "--font-weight-bold",
+			want:    "700",
+			defined: true,
+		},
+		{
+			name:  "font reset removes families",
+			css:   "@theme { --font-*: initial; }",
+			token: "--font-sans",
+		},
+		{
+			name:    "text reset keeps text shadows",
+			css:     "@theme { --text-*: initial; }",
+			token:   "--text-shadow-sm",
+			want:    "0 1px 2px rgb(0 0 0 / 0.15)",
+			defined: true,
+		},
+		{
+			name:  "text reset removes line heights",
+			css:   "@theme { --text-*: initial; }",
+			token: "--text-lg--line-height",
+		},
+		{
+			name:  "font weight reset",
+			css:   "@theme { --font-weight-*: initial; }",
+			token: "--font-weight-bold",
+		},
+		{
+			name:  "single token reset",
+			css:   "@theme { --text-lg: initial; }",
+			token: "--text-lg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme := NewTheme()
+			defaults := "@theme default { --color-blue-500: #3b82f6; --text-lg: 1.125rem; --text-lg--line-height: 1.75rem; " +
+				"--text-shadow-sm: 0 1px 2px rgb(0 0 0 / 0.15); --font-sans: ui-sans-serif; --font-weight-bold: 700; " +
+				"@keyframes spin { to { rotate: 360deg; } } }"
+			if err := theme.Parse(defaults); err != nil {
+				t.Fatal(err)
+			}
+			if err := theme.Parse(tt.css); err != nil {
+				t.Fatal(err)
+			}
+			got, defined := theme.Value(tt.token)
+			if defined != tt.defined || got != tt.want {
+				t.Errorf("Value(%q) = %q, %v; want %q, %v", tt.token, got, defined, tt.want, tt.defined)
+			}
+		})
+	}
+}
+
+func TestThemeKeyframes(t *testing.T) {
+	theme := NewTheme()
+	css := `@theme {
+		--animate-wiggle: wiggle 1s ease-in-out infinite;
+		@keyframes wiggle {
+			0%,   100% { transform: rotate(-3deg); opacity: 1 }
+			50% { transform: rotate(3deg); }
+		}
+	}`
+	if err := theme.Parse(css); err != nil {
+		t.Fatal(err)
+	}
+
+	keyframes, exists := theme.Keyframes("wiggle")
+	if !exists {
+		t.Fatal("keyframes wiggle not parsed")
+	}
+	want := []KeyframeStep{
+		{Selector: "0%, 100%", Declarations: []Declaration{{"transform", "rotate(-3deg)"}, {"opacity", "1"}}},
+		{Selector: "50%", Declarations: []Declaration{{"transform", "rotate(3deg)"}}},
+	}
+	if keyframes.Name != "wiggle" || !reflect.DeepEqual(keyframes.Steps, want) {
+		t.Errorf("Keyframes(wiggle) = %+v, want steps %+v", keyframes, want)
+	}
+	if value, _ := theme.Value("--animate-wiggle"); value != "wiggle 1s ease-in-out infinite" {
+		t.Errorf("Value(--animate-wiggle) = %q", value)
+	}
+}
+
+func TestThemeFullResetRemovesKeyframes(t *testing.T) {
+	theme := NewTheme()
+	if err := theme.Parse("@theme { @keyframes spin { to { rotate: 360deg; } } }"); err != nil {
+		t.Fatal(err)
+	}
+	if err := theme.Parse("@theme { --*: initial; }"); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := theme.Keyframes("spin"); exists {
+		t.Error("keyframes spin survived --*: initial")
+	}
+}
+
+func TestThemeOverride(t *testing.T) {
+	tests := []struct {
+		name   string
+		css    string
+		token  string
+		want   string
+		inline bool
+	}{
+		{
+			name:  "later value wins",
+			css:   "@theme { --color-blue-500: #0000ff; }",
+			token: "--color-blue-500",
+			want:  "#0000ff",
+		},
+		{
+			name:  "new token",
+			css:   "@theme { --color-brand-500: #123456; }",
+			token: "--color-brand-500",
+			want:  "#123456",
+		},
+		{
+			name:  "reference to another token",
+			css:   "@theme { --color-primary: var(--color-blue-500); }",
+			token: "--color-primary",
+			want:  "#3b82f6",
+		},
+		{
+			name:   "inline theme",
+			css:    "@theme inline { --color-primary: var(--brand); }",
+			token:  "--color-primary",
+			want:   "var(--brand)",
+			inline: true,
+		},
+		{
+			name:  "comments",
+			css:   "@theme { /* brand */ --text-lg: 1.2rem; /* --text-lg: 2rem; */ }",
+			token: "--text-lg",
+			want:  "1.2rem",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme := NewTheme()
+			if err := theme.Parse("@theme default { --color-blue-500: #3b82f6; --text-lg: 1.125rem; }"); err != nil {
+				t.Fatal(err)
+			}
+			if err := theme.Parse(tt.css); err != nil {
+				t.Fatal(err)
+			}
+			got, defined := theme.Value(tt.token)
+			if !defined || got != tt.want {
+				t.Errorf("Value(%q) = %q, %v; want %q", tt.token, got, defined, tt.want)
+			}
+			if token, _ := theme.Token(tt.token); token.Inline != tt.inline {
+				t.Errorf("Token(%q).Inline = %v, want %v", tt.token, token.Inline, tt.inline)
+			}
+		})
+	}
+}
+
+func TestThemeOverrideKeepsOrder(t *testing.T) {
+	theme := NewTheme()
+	if err := theme.Parse("@theme { --breakpoint-sm: 40rem; --breakpoint-md: 48rem; }"); err != nil {
+		t.Fatal(err)
+	}
+	if err := theme.Parse("@theme { --breakpoint-sm: 36rem; --breakpoint-xs: 20rem; }"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, token := range theme.Namespace("breakpoint") {
+		got = append(got, token.Name+"="+token.Value)
+	}
+	want := []string{"sm=36rem", "md=48rem", "xs=20rem"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Namespace(breakpoint) = %v, want %v", got, want)
+	}
+}
+
+func TestCustomVariantParsing(t *testing.T) {
+	tests := []struct {
+		css  string
+		want []string // Rules of the variant, nil if the declaration is invalid
+	}{
+		{"@custom-variant dark (&:where(.dark, .dark *));", []string{"&:where(.dark, .dark *)"}},
+		{"@custom-variant pointer-coarse (@media (pointer: coarse));", []string{"@media (pointer: coarse)"}},
+		{"@custom-variant dark { &:where(.dark, .dark *) { @slot; } }", []string{"&:where(.dark, .dark *)"}},
+		{
+			"@custom-variant hocus { @media (hover: hover) { &:hover, &:focus { @slot; } } }",
+			[]string{"@media (hover: hover)", "&:hover, &:focus"},
+		},
+		{"@custom-variant dark (&.dark", nil},
+		{"@custom-variant dark { &:hover { } }", nil},
+		{"@custom-variant;", nil},
+	}
+
+	for _, tt := range tests {
+		theme := NewTheme()
+		err := theme.Parse(tt.css)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%s: parsed as %+v, want an error", tt.css, theme.CustomVariants())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.css, err)
+			continue
+		}
+		variants := theme.CustomVariants()
+		if len(variants) != 1 || strings.Join(variants[0].Rules, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: got %+v, want rules %q", tt.css, variants, tt.want)
+		}
+	}
+}
+
+func TestCustomVariantRedefinition(t *testing.T) {
+	theme := NewTheme()
+	css := "@custom-variant dark (&.dark); @custom-variant print (@media print); @custom-variant dark (&.night);"
+	if err := theme.Parse(css); err != nil {
+		t.Fatal(err)
+	}
+	variants := theme.CustomVariants()
+	if len(variants) != 2 || variants[0].Name != "dark" || variants[0].Rules[0] != "&.night" {
+		t.Errorf("CustomVariants() = %+v, want dark redefined as &.night in first place", variants)
+	}
+}
@@ -0,0 +1,573 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tokenizer walks HTML, JSX and TSX source and records every static class
+// string found in a class or className attribute. It understands tags,
+// attributes, JSX expression containers, comments and string literals, so
+// class-like text inside comments or ordinary strings is never picked up.
+type tokenizer struct {
+	src        string
+	pos        int
+	doc        *Document
+	parser     *HTMLParser
+	open       []openElement // Elements currently open, innermost last
+	children   map[int]int   // Number of child elements seen per element ID
+	lineStarts []int
+}
+
+type openElement struct {
+	name string
+	id   int // Element ID, or the enclosing element's ID for fragments
+}
+
+// Elements whose content is never markup
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// HTML elements that never have children
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// Keywords after which a `<` or `/` starts an expression rather than
+// continuing one
+var expressionKeywords = map[string]bool{
+	"return": true, "yield": true, "case": true, "default": true,
+	"typeof": true, "void": true, "in": true, "of": true, "else": true,
+	"do": true, "await": true, "delete": true, "throw": true,
+}
+
+func newTokenizer(p *HTMLParser, doc *Document) *tokenizer {
+	t := &tokenizer{
+		src:        doc.Content,
+		doc:        doc,
+		parser:     p,
+		children:   make(map[int]int),
+		lineStarts: []int{0},
+	}
+
+	for i := 0; i < len(t.src); i++ {
+		if t.src[i] == '\n' {
+			t.lineStarts = append(t.lineStarts, i+1)
+		}
+	}
+	return t
+}
+
+func (t *tokenizer) run() {
+	// Component files are script at the top level; plain HTML and Vue files
+	// simply start with a tag, which the script scanner treats as markup.
+	for t.pos < len(t.src) {
+		t.scanScript()
+		if t.pos < len(t.src) {
+			// Unbalanced closing brace at the top level
+			t.pos++
+		}
+	}
+}
+
+// scanScript consumes JavaScript/TypeScript until EOF or until the unmatched
+// `}` that closes the current expression container.
+func (t *tokenizer) scanScript() {
+	depth := 0
+	exprStart := true
+	afterMarkup := false // Only space since the last markup, so a sibling may follow
+
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		markupAllowed := exprStart || afterMarkup
+		afterMarkup = false
+
+		switch {
+		case isSpace(c):
+			t.pos++
+			afterMarkup = markupAllowed
+		case c == '/' && t.peek(1) == '/':
+			t.skipLineComment()
+		case c == '/' && t.peek(1) == '*':
+			t.skipBlockComment()
+		case c == '\'' || c == '"':
+			t.skipString(c)
+			exprStart = false
+		case c == '`':
+			t.skipTemplate()
+			exprStart = false
+		case c == '{':
+			depth++
+			t.pos++
+			exprStart = true
+		case c == '}':
+			if depth == 0 {
+				return
+			}
+			depth--
+			t.pos++
+			exprStart = true
+		case c == '<' && markupAllowed && t.peek(1) == '!':
+			// A comment or doctype, which markup may follow
+			t.scanMarkup()
+			afterMarkup = true
+		case c == '<' && markupAllowed:
+			if t.scanMarkup() {
+				exprStart = false
+				afterMarkup = true
+				continue
+			}
+			t.pos++
+		case c == '/' && exprStart:
+			t.skipRegex()
+			exprStart = false
+		case isIdentStart(c):
+			word := t.readIdent()
+			exprStart = expressionKeywords[word]
+		case isDigit(c):
+			for t.pos < len(t.src) && (isIdentChar(t.src[t.pos]) || t.src[t.pos] == '.') {
+				t.pos++
+			}
+			exprStart = false
+		case c == ')' || c == ']':
+			t.pos++
+			exprStart = false
+		default:
+			t.pos++
+			exprStart = true
+		}
+	}
+}
+
+// scanMarkup consumes a tag, comment or doctype starting at `<`. It returns
+// false, leaving the position untouched, if the text is not markup.
+func (t *tokenizer) scanMarkup() bool {
+	switch {
+	case strings.HasPrefix(t.src[t.pos:], "<!--"):
+		t.skipPast("-->")
+		return true
+	case t.peek(1) == '!':
+		t.skipPast(">")
+		return true
+	case t.peek(1) == '>':
+		// JSX fragment, transparent for the element tree
+		t.pos += 2
+		t.scanChildren("", t.parentID())
+		return true
+	case isIdentStart(t.peek(1)):
+		return t.scanElement()
+	}
+	return false
+}
+
+func (t *tokenizer) scanElement() bool {
+	start := t.pos
+	refCount := len(t.doc.ClassRefs)
+	t.pos++
+	name := t.readTagName()
+	id := t.addElement(name, start)
+
+	// Not markup after all, e.g. a TypeScript generic: forget the element
+	rewind := func() bool {
+		t.pos = start
+		t.doc.ClassRefs = t.doc.ClassRefs[:refCount]
+		t.doc.Elements = t.doc.Elements[:id]
+		t.children[t.parentID()]--
+		return false
+	}
+
+	for {
+		t.skipSpace()
+		if t.pos >= len(t.src) {
+			return true
+		}
+
+		c := t.src[t.pos]
+		switch {
+		case c == '>':
+			t.pos++
+			t.scanContent(name, id)
+			return true
+		case c == '/' && t.peek(1) == '>':
+			t.pos += 2
+			return true
+		case c == '{':
+			// JSX spread attribute
+			t.pos++
+			t.scanScript()
+			t.skipByte('}')
+		case isAttrNameChar(c):
+			if !t.scanAttribute(id) {
+				return rewind()
+			}
+		default:
+			return rewind()
+		}
+	}
+}
+
+// addElement records a new element opened at offset start as a child of the
+// innermost open element.
+func (t *tokenizer) addElement(name string, start int) int {
+	id := len(t.doc.Elements)
+	parent := t.parentID()
+	t.children[parent]++
+
+	path := fmt.Sprintf("%s[%d]", name, t.children[parent])
+	if parent >= 0 {
+		path = t.doc.Elements[parent].Path + " > " + path
+	}
+
+	line, column := t.lineColumn(start)
+	t.doc.Elements = append(t.doc.Elements, Element{
+		ID:     id,
+		Name:   name,
+		Parent: parent,
+		Path:   path,
+		Line:   line,
+		Column: column,
+	})
+	return id
+}
+
+func (t *tokenizer) parentID() int {
+	if len(t.open) == 0 {
+		return -1
+	}
+	return t.open[len(t.open)-1].id
+}
+
+// lineColumn converts a byte offset into a 1-based line and column
+func (t *tokenizer) lineColumn(offset int) (int, int) {
+	line := sort.Search(len(t.lineStarts), func(i int) bool {
+		return t.lineStarts[i] > offset
+	})
+	return line, offset - t.lineStarts[line-1] + 1
+}
+
+func (t *tokenizer) scanAttribute(id int) bool {
+	attrStart := t.pos
+	for t.pos < len(t.src) && isAttrNameChar(t.src[t.pos]) {
+		t.pos++
+	}
+	attr := t.src[attrStart:t.pos]
+
+	afterName := t.pos
+	t.skipSpace()
+	if t.peek(0) != '=' {
+		// Boolean attribute
+		t.pos = afterName
+		return true
+	}
+	t.pos++
+	t.skipSpace()
+
+	ref := ClassRef{
+		Element:    t.doc.Elements[id].Name,
+		ElementID:  id,
+		Attr:       attr,
+		AttrStart:  attrStart,
+		ValueStart: t.pos,
+	}
+	isClass := attr == "class" || attr == "className"
+
+	switch c := t.peek(0); c {
+	case '"', '\'':
+		end := strings.IndexByte(t.src[t.pos+1:], c)
+		if end < 0 {
+			return false
+		}
+		ref.Start = t.pos + 1
+		ref.End = ref.Start + end
+		ref.Quote = c
+		t.pos = ref.End + 1
+	case '{':
+		t.pos++
+		exprStart := t.pos
+		t.scanScript()
+		exprEnd := t.pos
+		if !t.skipByte('}') {
+			return false
+		}
+		if !isClass {
+			return true
+		}
+		start, end, quote, ok := t.singleLiteral(exprStart, exprEnd)
+		if !ok {
+			ref.ValueEnd = t.pos
+			t.scanClassExpression(ref, exprStart, exprEnd)
+			return true
+		}
+		ref.Start, ref.End, ref.Quote = start, end, quote
+	default:
+		// Unquoted HTML attribute value
+		ref.Start = t.pos
+		for t.pos < len(t.src) && !isSpace(t.src[t.pos]) && t.src[t.pos] != '>' &&
+			!(t.src[t.pos] == '/' && t.peek(1) == '>') {
+			t.pos++
+		}
+		ref.End = t.pos
+		if ref.End == ref.Start {
+			return false
+		}
+	}
+	ref.ValueEnd = t.pos
+
+	if isClass {
+		t.addClassRef(ref)
+	}
+	return true
+}
+
+// singleLiteral reports whether src[start:end] is exactly one string literal
+// without substitutions, returning the offsets of its contents.
+func (t *tokenizer) singleLiteral(start, end int) (int, int, byte, bool) {
+	expr := t.src[start:end]
+	trimmed := strings.TrimSpace(expr)
+	if len(trimmed) < 2 {
+		return 0, 0, 0, false
+	}
+
+	quote := trimmed[0]
+	if (quote != '"' && quote != '\'' && quote != '`') || trimmed[len(trimmed)-1] != quote {
+		return 0, 0, 0, false
+	}
+
+	inner := trimmed[1 : len(trimmed)-1]
+	if strings.IndexByte(inner, quote) >= 0 || strings.IndexByte(inner, '\\') >= 0 {
+		return 0, 0, 0, false
+	}
+	if quote == '`' && strings.Contains(inner, "${") {
+		return 0, 0, 0, false
+	}
+
+	litStart := start + strings.Index(expr, trimmed) + 1
+	return litStart, litStart + len(inner), quote, true
+}
+
+func (t *tokenizer) addClassRef(ref ClassRef) {
+	for _, class := range strings.Fields(t.src[ref.Start:ref.End]) {
+		if t.parser.isTailwindClass(class) {
+			ref.Classes = append(ref.Classes, class)
+		}
+	}
+
+	if len(ref.Classes) > 0 {
+		t.doc.ClassRefs = append(t.doc.ClassRefs, ref)
+	}
+}
+
+// scanContent consumes everything between an opening tag and its closing tag.
+func (t *tokenizer) scanContent(name string, id int) {
+	// Only lowercase names are HTML elements; <Input> is a component
+	if voidElements[name] {
+		return
+	}
+
+	if rawTextElements[name] {
+		end := strings.Index(t.src[t.pos:], "</"+name)
+		if end < 0 {
+			t.pos = len(t.src)
+			return
+		}
+		t.pos += end
+		t.skipPast(">")
+		return
+	}
+
+	t.scanChildren(name, id)
+}
+
+func (t *tokenizer) scanChildren(name string, id int) {
+	t.open = append(t.open, openElement{name: name, id: id})
+	defer func() { t.open = t.open[:len(t.open)-1] }()
+
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+
+		switch {
+		case c == '<' && t.peek(1) == '/':
+			closeStart := t.pos
+			t.pos += 2
+			closeName := t.readTagName()
+			t.skipPast(">")
+
+			if closeName == name {
+				return
+			}
+			if t.isOpen(closeName) {
+				// Closes an ancestor; let it consume the tag
+				t.pos = closeStart
+				return
+			}
+		case c == '<':
+			if !t.scanMarkup() {
+				t.pos++
+			}
+		case c == '{':
+			t.pos++
+			t.scanScript()
+			t.skipByte('}')
+		default:
+			t.pos++
+		}
+	}
+}
+
+func (t *tokenizer) isOpen(name string) bool {
+	for i := len(t.open) - 2; i >= 0; i-- {
+		if t.open[i].name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tokenizer) skipString(quote byte) {
+	t.pos++
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		if c == '\\' {
+			t.pos += 2
+			continue
+		}
+		t.pos++
+		if c == quote || c == '\n' {
+			return
+		}
+	}
+}
+
+func (t *tokenizer) skipTemplate() {
+	t.pos++
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		switch {
+		case c == '\\':
+			t.pos += 2
+		case c == '`':
+			t.pos++
+			return
+		case c == '$' && t.peek(1) == '{':
+			t.pos += 2
+			t.scanScript()
+			t.skipByte('}')
+		default:
+			t.pos++
+		}
+	}
+}
+
+func (t *tokenizer) skipRegex() {
+	t.pos++
+	inClass := false
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		switch {
+		case c == '\\':
+			t.pos += 2
+			continue
+		case c == '\n':
+			return
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			t.pos++
+			for t.pos < len(t.src) && isIdentChar(t.src[t.pos]) {
+				t.pos++
+			}
+			return
+		}
+		t.pos++
+	}
+}
+
+func (t *tokenizer) skipLineComment() {
+	end := strings.IndexByte(t.src[t.pos:], '\n')
+	if end < 0 {
+		t.pos = len(t.src)
+		return
+	}
+	t.pos += end + 1
+}
+
+func (t *tokenizer) skipBlockComment() {
+	t.pos += 2
+	t.skipPast("*/")
+}
+
+func (t *tokenizer) skipPast(marker string) {
+	end := strings.Index(t.src[t.pos:], marker)
+	if end < 0 {
+		t.pos = len(t.src)
+		return
+	}
+	t.pos += end + len(marker)
+}
+
+func (t *tokenizer) skipSpace() {
+	for t.pos < len(t.src) && isSpace(t.src[t.pos]) {
+		t.pos++
+	}
+}
+
+func (t *tokenizer) skipByte(c byte) bool {
+	if t.peek(0) != c {
+		return false
+	}
+	t.pos++
+	return true
+}
+
+func (t *tokenizer) peek(offset int) byte {
+	if t.pos+offset < len(t.src) {
+		return t.src[t.pos+offset]
+	}
+	return 0
+}
+
+func (t *tokenizer) readIdent() string {
+	start := t.pos
+	for t.pos < len(t.src) && isIdentChar(t.src[t.pos]) {
+		t.pos++
+	}
+	return t.src[start:t.pos]
+}
+
+func (t *tokenizer) readTagName() string {
+	start := t.pos
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		if !isIdentChar(c) && c != '.' && c != '-' && c != ':' {
+			break
+		}
+		t.pos++
+	}
+	return t.src[start:t.pos]
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isAttrNameChar(c byte) bool {
+	return isIdentChar(c) || c == '-' || c == ':' || c == '.' || c == '@' || c == '#'
+}
@@ -0,0 +1,101 @@
+package parser
+
+import "testing"
+
+func TestTokenizerOffsets(t *testing.T) {
+	type ref struct {
+		classes string // Content[Start:End]
+		value   string // Content[ValueStart:ValueEnd]
+		element string
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []ref
+	}{
+		{
+			name:    "quoted attributes",
+			content: `<div class="p-4 m-2"><span class='text-lg'>x</span></div>`,
+			want:    []ref{{"p-4 m-2", `"p-4 m-2"`, "div"}, {"text-lg", `'text-lg'`, "span"}},
+		},
+		{
+			name:    "unquoted attribute",
+			content: `<div data-x="a>b" class=m-1>`,
+			want:    []ref{{"m-1", "m-1", "div"}},
+		},
+		{
+			name:    "expression containers",
+			content: `const A = () => <i className={"gap-2"}><b className={'it"s p-1'} /></i>;`,
+			want:    []ref{{"gap-2", `{"gap-2"}`, "i"}, {`it"s p-1`, `{'it"s p-1'}`, "b"}},
+		},
+		{
+			name:    "backtick string",
+			content: "const A = () => <p className={`flex gap-2`} />;",
+			want:    []ref{{"flex gap-2", "{`flex gap-2`}", "p"}},
+		},
+		{
+			name:    "comments and strings",
+			content: "const s = '<div class=\"p-4\">'; // <b class=\"x\">\n/* <i class=\"y\"> */\nconst A = () => <i className=\"gap-2\" />;",
+			want:    []ref{{"gap-2", `"gap-2"`, "i"}},
+		},
+		{
+			name:    "markup after comment and doctype",
+			content: `<!DOCTYPE html><html><!-- <div class="x"> --><body class="p-4"></body></html>`,
+			want:    []ref{{"p-4", `"p-4"`, "body"}},
+		},
+		{
+			name:    "top-level siblings",
+			content: "<div class=\"p-4\"></div>\n<!-- x -->\n<p class=\"m-2\"></p>",
+			want:    []ref{{"p-4", `"p-4"`, "div"}, {"m-2", `"m-2"`, "p"}},
+		},
+		{
+			name:    "comparison after markup",
+			content: `const a = <i className="p-1" />; const b = c <d; const e = <b className="m-1" />;`,
+			want:    []ref{{"p-1", `"p-1"`, "i"}, {"m-1", `"m-1"`, "b"}},
+		},
+		{
+			name:    "element inside expression",
+			content: `const A = () => <div>{a < b && <b className="m-1" />}</div>;`,
+			want:    []ref{{"m-1", `"m-1"`, "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		doc, err := NewHTMLParser().ParseContent(tt.content)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(doc.ClassRefs) != len(tt.want) {
+			t.Errorf("%s: got %d class refs, want %d", tt.name, len(doc.ClassRefs), len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			r := doc.ClassRefs[i]
+			got := ref{doc.Content[r.Start:r.End], doc.Content[r.ValueStart:r.ValueEnd], r.Element}
+			if got != want {
+				t.Errorf("%s: ref %d = %+v, want %+v", tt.name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestTokenizerElements(t *testing.T) {
+	doc, err := NewHTMLParser().ParseContent("<div class=\"a\">\n  <br/><p class=\"c\"></p>\n</div>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Element{
+		{ID: 0, Name: "div", Parent: -1, Path: "div[1]", Line: 1, Column: 1},
+		{ID: 1, Name: "br", Parent: 0, Path: "div[1] > br[1]", Line: 2, Column: 3},
+		{ID: 2, Name: "p", Parent: 0, Path: "div[1] > p[2]", Line: 2, Column: 8},
+	}
+	if len(doc.Elements) != len(want) {
+		t.Fatalf("got %d elements, want %d", len(doc.Elements), len(want))
+	}
+	for i := range want {
+		if doc.Elements[i] != want[i] {
+			t.Errorf("element %d = %+v, want %+v", i, doc.Elements[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,414 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsedUtility is a Tailwind class broken down along the v4 class grammar:
+// a variant stack, an optional "!" and "-", then a utility root with an
+// optional value and modifier. For example md:hover:!-mt-4 has the variants
+// md and hover, is important and negative, and has root mt with value 4.
+type ParsedUtility struct {
+	Raw       string
+	Variants  []Variant
+	Important bool
+	Negative  bool
+	Utility   string // The class without variants, "!" or "-", e.g. "mt-4"
+	Root      string
+	Value     *UtilityValue
+	Modifier  *UtilityValue
+	Property  string // Property name of an arbitrary property, e.g. "mask-type"
+}
+
+// Variant is one entry of a class's variant stack, e.g. hover, md,
+// group-hover/card or data-[state=open].
+type Variant struct {
+	Raw      string
+	Name     string        // Name without arbitrary value or modifier, "" for [&>svg]
+	Value    *UtilityValue // Arbitrary part, as in data-[state=open] or [&>svg]
+	Modifier string        // Text after "/", e.g. "card" in group-hover/card
+}
+
+type ValueKind int
+
+const (
+	NamedValue     ValueKind = iota // blue-500, 4, lg
+	ArbitraryValue                  // [37px], (--my-width)
+)
+
+type UtilityValue struct {
+	Kind     ValueKind
+	Text     string // The named value, or the decoded arbitrary value
+	DataType string // Type hint of an arbitrary value, e.g. "length" in [length:2px]
+	Fraction string // Value and modifier as a fraction, e.g. "1/2" in w-1/2
+}
+
+// Type hints accepted at the start of an arbitrary value
+var arbitraryDataTypes = map[string]bool{
+	"color": true, "length": true, "percentage": true, "number": true,
+	"integer": true, "url": true, "position": true, "bg-size": true,
+	"image": true, "family-name": true, "absolute-size": true,
+	"relative-size": true, "line-width": true, "angle": true, "shadow": true,
+	"vector": true, "any": true, "ratio": true,
+}
+
+// ParseUtility parses a class such as hover:bg-blue-500/50,
+// [&>svg]:w-[calc(100%-2rem)] or [mask-type:luminance].
+func ParseUtility(class string) (*ParsedUtility, error) {
+	segments, err := splitTopLevel(class, ':')
+	if err != nil {
+		return nil, fmt.Errorf("invalid class %q: %v", class, err)
+	}
+
+	u := &ParsedUtility{Raw: class}
+	for _, segment := range segments[:len(segments)-1] {
+		variant, err := parseVariant(segment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid class %q: %v", class, err)
+		}
+		u.Variants = append(u.Variants, variant)
+	}
+
+	utility := segments[len(segments)-1]
+	if strings.HasPrefix(utility, "!") {
+		u.Important = true
+		utility = utility[1:]
+	} else if strings.HasSuffix(utility, "!") {
+		u.Important = true
+		utility = utility[:len(utility)-1]
+	}
+	if utility == "" {
+		return nil, fmt.Errorf("invalid class %q: missing utility", class)
+	}
+
+	// Arbitrary property, [mask-type:luminance]
+	if strings.HasPrefix(utility, "[") && strings.HasSuffix(utility, "]") {
+		inner := utility[1 : len(utility)-1]
+		colon := strings.IndexByte(inner, ':')
+		if colon <= 0 || colon == len(inner)-1 {
+			return nil, fmt.Errorf("invalid class %q: arbitrary property needs a name and a value", class)
+		}
+		u.Utility = utility
+		u.Property = inner[:colon]
+		u.Value = &UtilityValue{Kind: ArbitraryValue, Text: DecodeArbitrary(inner[colon+1:])}
+		return u, nil
+	}
+
+	if strings.HasPrefix(utility, "-") {
+		u.Negative = true
+		utility = utility[1:]
+	}
+	u.Utility = utility
+
+	base := utility
+	if parts, err := splitTopLevel(utility, '/'); err != nil {
+		return nil, fmt.Errorf("invalid class %q: %v", class, err)
+	} else if len(parts) > 2 {
+		return nil, fmt.Errorf("invalid class %q: more than one modifier", class)
+	} else if len(parts) == 2 {
+		base = parts[0]
+		u.Modifier = parseModifier(parts[1])
+		if u.Modifier == nil {
+			return nil, fmt.Errorf("invalid class %q: empty modifier", class)
+		}
+	}
+
+	if err := u.parseRootAndValue(base); err != nil {
+		return nil, fmt.Errorf("invalid class %q: %v", class, err)
+	}
+	return u, nil
+}
+
+func (u *ParsedUtility) parseRootAndValue(base string) error {
+	// Arbitrary value, w-[37px] or bg-[color:var(--brand)]
+	if strings.HasSuffix(base, "]") {
+		open := strings.Index(base, "-[")
+		if open <= 0 {
+			return fmt.Errorf("arbitrary value without a utility")
+		}
+		u.Root = base[:open]
+		u.Value = parseArbitraryValue(base[open+2 : len(base)-1])
+		return nil
+	}
+
+	// CSS variable shorthand, bg-(--brand) or bg-(color:--brand)
+	if strings.HasSuffix(base, ")") {
+		open := strings.Index(base, "-(")
+		if open <= 0 {
+			return fmt.Errorf("variable value without a utility")
+		}
+		u.Root = base[:open]
+		u.Value = parseVariableValue(base[open+2 : len(base)-1])
+		return nil
+	}
+
+	// Longest known root wins, so border-b-2 is border-b with value 2
+	u.Root = base
+	for i := len(base); i > 0; i-- {
+		if i < len(base) && base[i] != '-' {
+			continue
+		}
+		if functionalRoots[base[:i]] {
+			u.Root = base[:i]
+			if i < len(base) {
+				u.Value = &UtilityValue{Kind: NamedValue, Text: base[i+1:]}
+			}
+			break
+		}
+	}
+
+	if u.Value != nil && u.Value.Text == "" {
+		return fmt.Errorf("empty value")
+	}
+	if u.Value != nil && u.Modifier != nil && u.Modifier.Kind == NamedValue {
+		u.Value.Fraction = u.Value.Text + "/" + u.Modifier.Text
+	}
+	return nil
+}
+
+// Base returns the utility without variants, important flag and modifier,
+// but with the negative sign, e.g. "-mt-4" for md:-mt-4.
+func (u *ParsedUtility) Base() string {
+	base := u.Utility
+	if u.Modifier != nil {
+		if slash := strings.LastIndexByte(base, '/'); slash >= 0 {
+			base = base[:slash]
+		}
+	}
+	if u.Negative {
+		return "-" + base
+	}
+	return base
+}
+
+// IsKnown reports whether the class is Tailwind: its root is a known
+// utility root or keyword, or it is an arbitrary property, and mapped finds
+// a conversion for it. Custom classes sharing a root, like col-md-6, have
+// none. A nil mapped accepts every class with a known root.
+func (u *ParsedUtility) IsKnown(mapped func(u *ParsedUtility) bool) bool {
+	if u.Property == "" && !functionalRoots[u.Root] && !staticUtilities[u.Utility] {
+		return false
+	}
+	return mapped == nil || mapped(u)
+}
+
+func parseVariant(segment string) (Variant, error) {
+	variant := Variant{Raw: segment, Name: segment}
+	if segment == "" {
+		return variant, fmt.Errorf("empty variant")
+	}
+
+	// Arbitrary variant, [&>svg] or [@supports(display:grid)]
+	if strings.HasPrefix(segment, "[") {
+		if !strings.HasSuffix(segment, "]") {
+			return variant, fmt.Errorf("unterminated arbitrary variant %q", segment)
+		}
+		variant.Name = ""
+		variant.Value = &UtilityValue{Kind: ArbitraryValue, Text: DecodeArbitrary(segment[1 : len(segment)-1])}
+		return variant, nil
+	}
+
+	parts, err := splitTopLevel(segment, '/')
+	if err != nil {
+		return variant, err
+	}
+	if len(parts) == 2 {
+		variant.Modifier = parts[1]
+	}
+	name := parts[0]
+
+	// Functional variant with an arbitrary value, data-[state=open], or a
+	// container query, @[400px]
+	if strings.HasSuffix(name, "]") {
+		open := strings.Index(name, "-[")
+		if strings.HasPrefix(name, "@[") {
+			variant.Value = parseArbitraryValue(name[2 : len(name)-1])
+			name = "@"
+		} else if open <= 0 {
+			return variant, fmt.Errorf("invalid variant %q", segment)
+		} else {
+			variant.Value = parseArbitraryValue(name[open+2 : len(name)-1])
+			name = name[:open]
+		}
+	}
+	variant.Name = name
+	return variant, nil
+}
+
+func parseModifier(text string) *UtilityValue {
+	switch {
+	case text == "":
+		return nil
+	case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+		return &UtilityValue{Kind: ArbitraryValue, Text: DecodeArbitrary(text[1 : len(text)-1])}
+	case strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")"):
+		return parseVariableValue(text[1 : len(text)-1])
+	}
+	return &UtilityValue{Kind: NamedValue, Text: text}
+}
+
+func parseArbitraryValue(text string) *UtilityValue {
+	value := &UtilityValue{Kind: ArbitraryValue}
+	if colon := strings.IndexByte(text, ':'); colon > 0 && arbitraryDataTypes[text[:colon]] {
+		value.DataType = text[:colon]
+		text = text[colon+1:]
+	}
+	value.Text = DecodeArbitrary(text)
+	return value
+}
+
+func parseVariableValue(text string) *UtilityValue {
+	value := &UtilityValue{Kind: ArbitraryValue}
+	if colon := strings.IndexByte(text, ':'); colon > 0 && arbitraryDataTypes[text[:colon]] {
+		value.DataType = text[:colon]
+		text = text[colon+1:]
+	}
+	value.Text = "var(" + DecodeArbitrary(text) + ")"
+	return value
+}
+
+// DecodeArbitrary turns the underscores of an arbitrary value into spaces,
+// keeping escaped underscores and those inside url(...).
+func DecodeArbitrary(text string) string {
+	var out strings.Builder
+	urlDepth := 0
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '_':
+			out.WriteByte('_')
+			i++
+			continue
+		case c == '(':
+			depth++
+			if strings.HasSuffix(text[:i], "url") && urlDepth == 0 {
+				urlDepth = depth
+			}
+		case c == ')':
+			if depth == urlDepth {
+				urlDepth = 0
+			}
+			depth--
+		case c == '_' && urlDepth == 0:
+			out.WriteByte(' ')
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return spaceMathOperators(out.String())
+}
+
+// Functions whose arguments are math expressions
+var mathFunctions = setOf("calc", "min", "max", "clamp")
+
+// spaceMathOperators puts spaces around the operators inside calc(), min(),
+// max() and clamp(), as CSS requires for + and -: calc(100%-2rem) becomes
+// calc(100% - 2rem). Signs, as in calc(-1*var(--x)), and the arguments of
+// other functions, like var(--a-b), are left alone.
+func spaceMathOperators(value string) string {
+	if !strings.Contains(value, "(") {
+		return value
+	}
+
+	var out strings.Builder
+	var math []bool // Whether each open parenthesis holds a math expression
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '(':
+			name := value[:i]
+			name = name[strings.LastIndexFunc(name, func(r rune) bool {
+				return !(r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+			})+1:]
+			if strings.HasSuffix(name, "-") {
+				// A group after an operator, as in calc(100%-(2*var(--x)))
+				name = ""
+			}
+			inMath := len(math) > 0 && math[len(math)-1]
+			math = append(math, mathFunctions[strings.ToLower(name)] || name == "" && inMath)
+		case c == ')':
+			if len(math) > 0 {
+				math = math[:len(math)-1]
+			}
+		case strings.IndexByte("+-*/", c) >= 0 && len(math) > 0 && math[len(math)-1]:
+			written := strings.TrimRight(out.String(), " ")
+			if isMathOperator(written, value[i+1:], c) {
+				out.Reset()
+				out.WriteString(written)
+				out.WriteString(" " + string(c) + " ")
+				for i+1 < len(value) && value[i+1] == ' ' {
+					i++
+				}
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
+
+// isMathOperator reports whether an operator between before and after is
+// one, rather than a sign, an exponent or part of an identifier
+func isMathOperator(before, after string, operator byte) bool {
+	if before == "" || after == "" {
+		return false
+	}
+	prev := before[len(before)-1]
+	if strings.IndexByte("(,+-*/", prev) >= 0 {
+		return false
+	}
+	if operator == '+' || operator == '-' {
+		next := after[0]
+		isLetter := func(b byte) bool { return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' }
+		// Exponents like 1e-3, and dashes inside identifiers
+		if (prev == 'e' || prev == 'E') && len(before) > 1 && before[len(before)-2] >= '0' && before[len(before)-2] <= '9' && next >= '0' && next <= '9' {
+			return false
+		}
+		if operator == '-' && isLetter(prev) && isLetter(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitTopLevel splits s at every sep that is not inside brackets,
+// parentheses or quotes.
+func splitTopLevel(s string, sep byte) ([]string, error) {
+	var parts []string
+	var stack []byte
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\\':
+			i++
+		case (c == '\'' || c == '"') && len(stack) > 0:
+			quote = c
+		case c == '[' || c == '(':
+			stack = append(stack, c)
+		case c == ']' || c == ')':
+			if len(stack) == 0 || (c == ']') != (stack[len(stack)-1] == '[') {
+				return nil, fmt.Errorf("unbalanced %q", c)
+			}
+			stack = stack[:len(stack)-1]
+		case c == sep && len(stack) == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	if len(stack) > 0 || quote != 0 {
+		return nil, fmt.Errorf("unterminated %q", stack)
+	}
+	return append(parts, s[start:]), nil
+}
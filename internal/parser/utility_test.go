@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeArbitrary(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"37px", "37px"},
+		{"200px_1fr", "200px 1fr"},
+		{`a\_b`, "a_b"},
+		{"url(/a_b.png)", "url(/a_b.png)"},
+		{"calc(100%-2rem)", "calc(100% - 2rem)"},
+		{"calc(100%_-_2rem)", "calc(100% - 2rem)"},
+		{"calc(100%/3)", "calc(100% / 3)"},
+		{"min(100vw,calc(var(--x)+1rem))", "min(100vw,calc(var(--x) + 1rem))"},
+		{"clamp(1rem,2.5vw+1rem,3rem)", "clamp(1rem,2.5vw + 1rem,3rem)"},
+		{"calc(100vh-(2*var(--h)))", "calc(100vh - (2 * var(--h)))"},
+		{"calc(-1*var(--a-b))", "calc(-1 * var(--a-b))"},
+		{"calc(1px*-1)", "calc(1px * -1)"},
+		{"calc(1e-3px*2)", "calc(1e-3px * 2)"},
+		{"var(--a-b)", "var(--a-b)"},
+		{"translate(-50%,-50%)", "translate(-50%,-50%)"},
+	}
+
+	for _, tt := range tests {
+		if got := DecodeArbitrary(tt.in); got != tt.want {
+			t.Errorf("DecodeArbitrary(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseUtility(t *testing.T) {
+	type parsed struct {
+		variants  string // Variant names, comma separated
+		important bool
+		negative  bool
+		root      string
+		value     string // Value text, in brackets when arbitrary
+		dataType  string
+		fraction  string
+		modifier  string
+		property  string
+	}
+	tests := []struct {
+		class string
+		want  parsed
+	}{
+		{"flex", parsed{root: "flex"}},
+		{"mt-4", parsed{root: "mt", value: "4"}},
+		{"border-b-2", parsed{root: "border-b", value: "2"}},
+		{"md:hover:!-mt-4", parsed{variants: "md,hover", important: true, negative: true, root: "mt", value: "4"}},
+		{"-mt-4!", parsed{important: true, negative: true, root: "mt", value: "4"}},
+		{"bg-blue-500/50", parsed{root: "bg", value: "blue-500", fraction: "blue-500/50", modifier: "50"}},
+		{"bg-red-500/[0.3]", parsed{root: "bg", value: "red-500", modifier: "[0.3]"}},
+		{"w-1/2", parsed{root: "w", value: "1", fraction: "1/2", modifier: "2"}},
+		{"w-[37px]", parsed{root: "w", value: "[37px]"}},
+		{"bg-[color:var(--brand)]", parsed{root: "bg", value: "[var(--brand)]", dataType: "color"}},
+		{"grid-cols-[200px_1fr]", parsed{root: "grid-cols", value: "[200px 1fr]"}},
+		{"bg-(--brand)", parsed{root: "bg", value: "[var(--brand)]"}},
+		{"text-(length:--size)", parsed{root: "text", value: "[var(--size)]", dataType: "length"}},
+		{"[mask-type:luminance]", parsed{property: "mask-type", value: "[luminance]"}},
+		{"group-hover/card:underline", parsed{variants: "group-hover", root: "underline"}},
+		{"data-[state=open]:block", parsed{variants: "data", root: "block"}},
+		{"[&>svg]:w-4", parsed{variants: "", root: "w", value: "4"}},
+	}
+
+	for _, tt := range tests {
+		u, err := ParseUtility(tt.class)
+		if err != nil {
+			t.Errorf("ParseUtility(%q): %v", tt.class, err)
+			continue
+		}
+		got := parsed{important: u.Important, negative: u.Negative, root: u.Root, property: u.Property}
+		var names []string
+		for _, variant := range u.Variants {
+			names = append(names, variant.Name)
+		}
+		got.variants = strings.Join(names, ",")
+		if u.Value != nil {
+			got.value, got.dataType, got.fraction = u.Value.Text, u.Value.DataType, u.Value.Fraction
+			if u.Value.Kind == ArbitraryValue {
+				got.value = "[" + got.value + "]"
+			}
+		}
+		if u.Modifier != nil {
+			got.modifier = u.Modifier.Text
+			if u.Modifier.Kind == ArbitraryValue {
+				got.modifier = "[" + got.modifier + "]"
+			}
+		}
+		if got != tt.want {
+			t.Errorf("ParseUtility(%q) = %+v, want %+v", tt.class, got, tt.want)
+		}
+	}
+}
+
+func TestParseUtilityErrors(t *testing.T) {
+	for _, class := range []string{"!", "hover:", "[mask-type]", "bg-red-500/50/20", "bg-blue/", "-[37px]", "w-[37px"} {
+		if _, err := ParseUtility(class); err == nil {
+			t.Errorf("ParseUtility(%q) succeeded, want an error", class)
+		}
+	}
+}
+
+func TestIsKnown(t *testing.T) {
+	// Stands in for the converter's mappings, which convert these
+	mapped := func(u *ParsedUtility) bool {
+		return setOf("col-span-2", "p-4", "container", "[color:red]")[u.Utility]
+	}
+
+	tests := []struct {
+		class  string
+		known  bool // With the mapper
+		rooted bool // Without one, by the root alone
+	}{
+		{"col-span-2", true, true},
+		{"md:p-4", true, true},
+		{"container", true, true},
+		{"[color:red]", true, true},
+		{"col-md-6", false, true},
+		{"content-wrapper", false, true},
+		{"btn", false, false},
+		{"btn-primary", false, false},
+	}
+
+	for _, tt := range tests {
+		u, err := ParseUtility(tt.class)
+		if err != nil {
+			t.Fatalf("ParseUtility(%q): %v", tt.class, err)
+		}
+		if got := u.IsKnown(mapped); got != tt.known {
+			t.Errorf("%s: IsKnown(mapped) = %v, want %v", tt.class, got, tt.known)
+		}
+		if got := u.IsKnown(nil); got != tt.rooted {
+			t.Errorf("%s: IsKnown(nil) = %v, want %v", tt.class, got, tt.rooted)
+		}
+	}
+}
+
+func TestUtilityMapperKeepsCustomClasses(t *testing.T) {
+	p := NewHTMLParser()
+	p.SetUtilityMapper(func(u *ParsedUtility) bool { return u.Utility == "p-4" })
+	doc, err := p.ParseContent(`<div class="col-md-6 p-4 hover:p-4 hover:col-md-6"></div>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.ClassRefs) != 1 || strings.Join(doc.ClassRefs[0].Classes, " ") != "p-4 hover:p-4" {
+		t.Errorf("class refs = %+v, want the p-4 classes only", doc.ClassRefs)
+	}
+}